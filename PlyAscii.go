@@ -0,0 +1,261 @@
+package plyReaderRealsense
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// plyGetElementPropertiesAscii decodes the elements of an ascii file, each element being a line of whitespace-separated values in the order of its properties
+func plyGetElementPropertiesAscii(reader *bufio.Reader, props []PlyProperty, data []PlyPropertyData, num_elems int) error {
+	for i := 0; i < num_elems; i++ {
+		fields, err := plyReadAsciiFields(reader)
+		if err != nil {
+			return err
+		}
+
+		// consume the values of the line property after property
+		next := 0
+		for j := 0; j < len(props); j++ {
+			if props[j].Is_list == 0 {
+				if next >= len(fields) {
+					return errors.New("missing value for property " + props[j].Name)
+				}
+				value, err := strconv.ParseFloat(fields[next], 64)
+				if err != nil {
+					return err
+				}
+				data[j].Values[i] = value
+				next++
+				continue
+			}
+
+			// a list starts with the number of its values
+			if next >= len(fields) {
+				return errors.New("missing count for list " + props[j].Name)
+			}
+			count, err := strconv.Atoi(fields[next])
+			if err != nil {
+				return err
+			}
+			next++
+			if count < 0 || next+count > len(fields) {
+				return errors.New("missing values for list " + props[j].Name)
+			}
+			list := make([]float64, count)
+			for k := 0; k < count; k++ {
+				list[k], err = strconv.ParseFloat(fields[next+k], 64)
+				if err != nil {
+					return err
+				}
+			}
+			data[j].Lists[i] = list
+			next += count
+		}
+	}
+	return nil
+}
+
+// plyReadAsciiFields reads the next non empty line of data and splits it into its values
+func plyReadAsciiFields(reader *bufio.Reader) ([]string, error) {
+	for {
+		line, err := reader.ReadString('\n')
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			return fields, nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+}
+
+// plyReadAsciiToken reads the next whitespace-separated value, whatever line it is on
+func plyReadAsciiToken(reader *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(token) > 0 {
+				return string(token), nil
+			}
+			if err == io.EOF {
+				return "", io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			if len(token) > 0 {
+				return string(token), nil
+			}
+			continue
+		}
+		token = append(token, c)
+	}
+}
+
+// plyReadAsciiValues fills the numbers contained in value (a pointer, slice, array or struct of numbers) with the next values of an ascii file
+func plyReadAsciiValues(reader *bufio.Reader, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		return plyReadAsciiValues(reader, value.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := plyReadAsciiValues(reader, value.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if err := plyReadAsciiValues(reader, value.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	token, err := plyReadAsciiToken(reader)
+	if err != nil {
+		return err
+	}
+	number, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return err
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(int64(number))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(number))
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(number)
+	default:
+		return errors.New("cannot read ascii values into " + value.Type().String())
+	}
+	return nil
+}
+
+// plyPutElementPropertiesAscii writes the elements held by the columns of data, checked against the properties props, as lines of values separated by spaces
+func plyPutElementPropertiesAscii(w io.Writer, props []PlyProperty, data []PlyPropertyData) error {
+	for j := 0; j < len(props); j++ {
+		prop := props[j]
+		if PlyTypeSize(plyFileType(prop)) == 0 || (prop.Is_list == 1 && PlyTypeSize(plyFileCountType(prop)) == 0) {
+			return errors.New("unknown type for property " + prop.Name)
+		}
+	}
+
+	var line []byte
+	num_elems := PlyColumnsLength(data)
+	for i := 0; i < num_elems; i++ {
+		line = line[:0]
+		for j := 0; j < len(props); j++ {
+			if j > 0 {
+				line = append(line, ' ')
+			}
+			prop := props[j]
+			if prop.Is_list == 0 {
+				line = plyAppendAsciiScalar(line, plyFileType(prop), data[j].Values[i])
+				continue
+			}
+			list := data[j].Lists[i]
+			line = plyAppendAsciiScalar(line, plyFileCountType(prop), float64(len(list)))
+			for _, value := range list {
+				line = plyAppendAsciiScalar(append(line, ' '), plyFileType(prop), value)
+			}
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plyAsciiElements checks that numbers hold whole elements laid out as the properties of elem, each scalar property taking one number and each list its length followed by its values, and that every value fits its type, and returns the number of elements
+func plyAsciiElements(elem *PlyElement, numbers []float64) (int, error) {
+	if len(elem.props) == 0 && len(numbers) > 0 {
+		return 0, fmt.Errorf("no property declared for element %s", elem.name)
+	}
+	n := 0
+	for next := 0; next < len(numbers); n++ {
+		for _, prop := range elem.props {
+			if next >= len(numbers) {
+				return 0, fmt.Errorf("%d values do not make whole elements %s", len(numbers), elem.name)
+			}
+			if prop.Is_list == 0 {
+				if err := plyCheckValue(plyFileType(prop), numbers[next], prop.Name); err != nil {
+					return 0, err
+				}
+				next++
+				continue
+			}
+			count := numbers[next]
+			if count < 0 || count != float64(int(count)) || int(count) > len(numbers)-next-1 {
+				return 0, fmt.Errorf("bad length %g for list %s", count, prop.Name)
+			}
+			if err := plyCheckList(prop, numbers[next+1:next+1+int(count)]); err != nil {
+				return 0, err
+			}
+			next += 1 + int(count)
+		}
+	}
+	return n, nil
+}
+
+// plyWriteAsciiElements writes the elements held by numbers, checked by plyAsciiElements, one element per line with each value in the type of its property
+func plyWriteAsciiElements(w io.Writer, props []PlyProperty, numbers []float64) error {
+	var line []byte
+	for next := 0; next < len(numbers); {
+		line = line[:0]
+		for j, prop := range props {
+			if j > 0 {
+				line = append(line, ' ')
+			}
+			if prop.Is_list == 0 {
+				line = plyAppendAsciiScalar(line, plyFileType(prop), numbers[next])
+				next++
+				continue
+			}
+			count := int(numbers[next])
+			line = plyAppendAsciiScalar(line, plyFileCountType(prop), numbers[next])
+			for _, value := range numbers[next+1 : next+1+count] {
+				line = plyAppendAsciiScalar(append(line, ' '), plyFileType(prop), value)
+			}
+			next += 1 + count
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plyAppendAsciiScalar writes a value converted to the given type at the end of b, the values being checked against the range of the type by plyColumnsElement or plyAsciiElements
+func plyAppendAsciiScalar(b []byte, typ int, value float64) []byte {
+	switch typ {
+	case PLY_CHAR:
+		return strconv.AppendInt(b, int64(int8(int64(value))), 10)
+	case PLY_UCHAR:
+		return strconv.AppendUint(b, uint64(uint8(int64(value))), 10)
+	case PLY_SHORT:
+		return strconv.AppendInt(b, int64(int16(int64(value))), 10)
+	case PLY_USHORT:
+		return strconv.AppendUint(b, uint64(uint16(int64(value))), 10)
+	case PLY_INT:
+		return strconv.AppendInt(b, int64(int32(int64(value))), 10)
+	case PLY_UINT:
+		return strconv.AppendUint(b, uint64(uint32(int64(value))), 10)
+	case PLY_FLOAT:
+		return strconv.AppendFloat(b, float64(float32(value)), 'g', -1, 32)
+	case PLY_DOUBLE:
+		return strconv.AppendFloat(b, value, 'g', -1, 64)
+	}
+	return b
+}
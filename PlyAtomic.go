@@ -0,0 +1,68 @@
+package plyReaderRealsense
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+/* PlyAbort closes a file opened for writing without keeping what was written : the temporary file is removed and the file of the final name, if any, is left untouched. */
+func PlyAbort(plyfile *PlyFile) error {
+	if plyfile == nil || plyfile.Fp == nil {
+		return nil
+	}
+	plyfile.buffer, plyfile.compressor, plyfile.counts_at = nil, nil, nil
+	err := plyfile.Fp.Close()
+	if plyfile.temp != "" {
+		if rerr := os.Remove(plyfile.temp); err == nil {
+			err = rerr
+		}
+		plyfile.temp = ""
+	}
+	return err
+}
+
+// plyCreateTemp creates the temporary file the data of filename are written to, in the same directory so that it can be renamed. It takes the permissions of the file it replaces, or those os.Create gives, 0666 less the umask.
+func plyCreateTemp(filename string) (*os.File, error) {
+	// opened by hand rather than by os.CreateTemp, whose 0600 would hide the umask
+	var f *os.File
+	var err error
+	for try := 0; try < 10000; try++ {
+		name := filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".tmp")
+		f, err = os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if !os.IsExist(err) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(filename); err == nil {
+		if err = f.Chmod(info.Mode().Perm()); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// plyCommit renames the closed temporary file to filename if it was written without error, and removes it otherwise
+func plyCommit(temp string, filename string, err error) error {
+	if err == nil {
+		err = os.Rename(temp, filename)
+	}
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+
+	// make the rename itself durable, where directories can be synced
+	if dir, derr := os.Open(filepath.Dir(filename)); derr == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package plyReaderRealsense
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// listDir returns the names of the files of a directory
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestAtomicWrite(t *testing.T) {
+	previous := "previous content"
+	tests := []struct {
+		name     string
+		existing bool // a file of the final name exists before the writing
+		abort    bool
+		bad      bool // the writing fails
+	}{
+		{"closed", false, false, false},
+		{"aborted", false, true, false},
+		{"aborted over a file", true, true, false},
+		{"failed writer", true, false, true},
+		{"replaced", true, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "cloud.ply")
+			if test.existing {
+				if err := os.WriteFile(filename, []byte(previous), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if test.bad {
+				// the columns do not match the header : the writer aborts
+				doc := &PlyDocument{Format: PLY_BINARY_LE, Elements: []PlyDocumentElement{{Name: "vertex", Properties: columns(PLY_FLOAT, []string{"x", "y"}, []float64{1, 2}, []float64{1})}}}
+				if err := PlyWriteDocument(filename, doc); err == nil {
+					t.Fatal("no error")
+				}
+			} else {
+				version := float32(1)
+				plyfile, err := PlyOpenForWriting(filename, 1, []string{"vertex"}, PLY_BINARY_LE, &version)
+				if err != nil {
+					t.Fatal(err)
+				}
+				PlyDescribeProperty(plyfile, "vertex", scalarProps(PLY_FLOAT, "x")[0])
+				PlyElementCount(plyfile, "vertex", 1)
+				PlyHeaderComplete(plyfile)
+				PlyPutElementSetup(plyfile, "vertex")
+				PlyPutElementHuge(plyfile, []float32{1})
+				writing := 1
+				if test.existing {
+					writing = 2
+				}
+				if len(listDir(t, dir)) != writing {
+					t.Errorf("no temporary file while writing : %v", listDir(t, dir))
+				}
+				if test.abort {
+					err = PlyAbort(plyfile)
+				} else {
+					err = PlyClose(plyfile)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// only the final file is left, with the new content only if the writing succeeded
+			names := listDir(t, dir)
+			kept := test.existing || (!test.abort && !test.bad)
+			if (kept && (len(names) != 1 || names[0] != "cloud.ply")) || (!kept && len(names) != 0) {
+				t.Fatalf("files left : %v", names)
+			}
+			if !kept {
+				return
+			}
+			content, _ := os.ReadFile(filename)
+			info, _ := os.Stat(filename)
+			if (test.abort || test.bad) != (string(content) == previous) {
+				t.Errorf("content %q", content)
+			}
+			if test.existing && info.Mode().Perm() != 0600 {
+				t.Errorf("mode %v, want the mode of the replaced file", info.Mode().Perm())
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package plyReaderRealsense
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestAtomicWriteUmask(t *testing.T) {
+	for _, test := range []struct {
+		umask int
+		mode  os.FileMode
+	}{{0, 0666}, {022, 0644}, {027, 0640}, {077, 0600}} {
+		old := syscall.Umask(test.umask)
+		filename := filepath.Join(t.TempDir(), "cloud.ply")
+		version := float32(1)
+		plyfile, err := PlyOpenForWriting(filename, 1, []string{"vertex"}, PLY_BINARY_LE, &version)
+		syscall.Umask(old)
+		if err != nil {
+			t.Fatal(err)
+		}
+		PlyDescribeProperty(plyfile, "vertex", *New_property("x", PLY_FLOAT, PLY_FLOAT, 0, 0, 0, 0, 0))
+		PlyElementCount(plyfile, "vertex", 1)
+		PlyHeaderComplete(plyfile)
+		PlyPutElementSetup(plyfile, "vertex")
+		PlyPutElementHuge(plyfile, []float32{1})
+		if err = PlyClose(plyfile); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != test.mode {
+			t.Errorf("umask %03o: mode %v, want %v as os.Create gives", test.umask, info.Mode().Perm(), test.mode)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package plyReaderRealsense
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// plyDecompress returns a reader decompressing the content of reader on the fly when it starts with the magic bytes of gzip or bzip2, and reader itself otherwise. The second return tells whether the content is compressed.
+func plyDecompress(reader *bufio.Reader) (*bufio.Reader, bool, error) {
+	magic, _ := reader.Peek(3)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, true, err
+		}
+		return bufio.NewReader(gz), true, nil
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return bufio.NewReader(bzip2.NewReader(reader)), true, nil
+	}
+	return reader, false, nil
+}
+
+// plyCompressor returns a writer compressing what is written into w when the name of the file ends with .gz, nil otherwise
+func plyCompressor(filename string, w io.Writer) io.WriteCloser {
+	if strings.HasSuffix(strings.ToLower(filename), ".gz") {
+		return gzip.NewWriter(w)
+	}
+	return nil
+}
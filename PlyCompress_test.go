@@ -0,0 +1,79 @@
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const smallPLY = "ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n1 2 3\n4 5 6\n"
+
+// smallPLY compressed by bzip2, which the standard library only decompresses
+const smallBzip2 = "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\x85\xfe\xb9\x5d\x00\x00\x28\x5b\x80\x00\x10\x40\x01\x7f\x00\x00\x00\xaf\x67\xdd\x70\x20\x00\x48\x82\x4c\x9e\xa3\x34\x6a\x01\xa3\x1a\x83\x6a\x9a\x9a\x68\xd0\xd1\xa0\x00\xd2\xa7\x06\x64\x8b\x65\xdb\x91\xf3\xa6\x0c\x48\x02\x0b\x45\x00\x22\x0e\x7a\x77\xb6\x1c\x5e\xb6\xd6\xe4\x71\x66\x15\x42\x1e\xf1\xf4\xe8\x5b\x4a\x89\x0c\x3f\x42\x29\x56\xd0\x19\x29\xac\xdc\x58\x96\x09\xf1\x69\x0e\xfc\x5d\xc9\x14\xe1\x42\x42\x17\xfa\xe5\x74"
+
+func TestCompressedDetection(t *testing.T) {
+	tests := []struct {
+		name    string // the name does not matter, the content is recognized by its first bytes
+		content []byte
+	}{
+		{"plain.ply", []byte(smallPLY)},
+		{"cloud.ply.gz", gzipped(smallPLY)},
+		{"cloud.ply.bz2", []byte(smallBzip2)},
+		{"gzip_without_suffix.ply", gzipped(smallPLY)},
+		{"bzip2_without_suffix.ply", []byte(smallBzip2)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := writeTestFile(t, test.name, "", test.content)
+			plyfile, err := OpenPLY(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer PlyClose(plyfile)
+			data, err := PlyGetElementPropertiesE(plyfile, "vertex")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if z := PlyFindProperty(data, "z"); z == nil || !reflect.DeepEqual(z.Values, []float64{3, 6}) {
+				t.Errorf("z = %v", z)
+			}
+
+			// the whole content is also read from a stream
+			plyfile, err = PlyOpenReader(bytes.NewReader(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = PlyGetElementPropertiesE(plyfile, "vertex"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestWriteGzip(t *testing.T) {
+	doc, err := PlyReadDocument(writeTestFile(t, "small.ply", smallPLY, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"out.ply.gz", "out.ply"} {
+		filename := filepath.Join(t.TempDir(), name)
+		if err = PlyWriteDocument(filename, doc); err != nil {
+			t.Fatal(err)
+		}
+		content, _ := os.ReadFile(filename)
+		_, gzerr := gzip.NewReader(bytes.NewReader(content))
+		if (gzerr == nil) != (filepath.Ext(name) == ".gz") {
+			t.Errorf("%s: compressed %t", name, gzerr == nil)
+		}
+		read, err := PlyReadDocument(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(read.Elements, doc.Elements) {
+			t.Errorf("%s: elements read back %v", name, read.Elements)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package plyReaderRealsense
+
+import (
+	"fmt"
+)
+
+// whole content of a PLY file : its header and the columns of every property of every element, including the ones no reader of this package models
+type PlyDocument struct {
+	Format   int     // PLY_ASCII, PLY_BINARY_LE or PLY_BINARY_BE
+	Version  float32 // version number of the format
+	Comments []string
+	ObjInfo  []string
+	Elements []PlyDocumentElement // elements in the order of the header
+}
+
+// one type of element of a PlyDocument
+type PlyDocumentElement struct {
+	Name       string
+	Count      int               // number of elements, only used when the element has no property
+	Properties []PlyPropertyData // columns of the properties in the order of the header
+}
+
+/* PlyReadDocument reads the header and every element of a PLY file into a PlyDocument, which PlyWriteDocument writes back unchanged apart from what the caller modified. The properties keep the type names of the header (float or float32...). */
+func PlyReadDocument(filename string) (*PlyDocument, error) {
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer PlyClose(plyfile)
+
+	return PlyGetDocument(plyfile)
+}
+
+/* PlyGetDocument reads every element of an open PLY file into a PlyDocument, the file being opened by OpenPLY, PlyOpenReader or PlyOpenReaderAt and none of its elements being read yet. */
+func PlyGetDocument(plyfile *PlyFile) (*PlyDocument, error) {
+	doc := &PlyDocument{
+		Format:   plyfile.file_type,
+		Version:  plyfile.version,
+		Comments: append([]string(nil), plyfile.comments...),
+		ObjInfo:  append([]string(nil), plyfile.obj_info...),
+	}
+	for _, name := range PlyGetElementNames(plyfile) {
+		data, err := PlyGetElementPropertiesE(plyfile, name)
+		if err != nil {
+			return nil, err
+		}
+		_, num_elems, _ := PlyGetElementDescription(plyfile, name)
+		doc.Elements = append(doc.Elements, PlyDocumentElement{Name: name, Count: num_elems, Properties: data})
+	}
+	return doc, nil
+}
+
+/* Element returns the element of the document with the given name, nil if there is none. Its properties are found with PlyFindProperty. */
+func (doc *PlyDocument) Element(name string) *PlyDocumentElement {
+	for i := range doc.Elements {
+		if doc.Elements[i].Name == name {
+			return &doc.Elements[i]
+		}
+	}
+	return nil
+}
+
+/* PlyWriteDocument writes a PlyDocument as a PLY file in the format of the document. The number of elements of a type is the length of the columns of its properties, which must all have the same length, PlyPutElementProperties returning an error otherwise. */
+func PlyWriteDocument(filename string, doc *PlyDocument) (err error) {
+	names := make([]string, len(doc.Elements))
+	counts := make([]int, len(doc.Elements))
+	for i, elem := range doc.Elements {
+		names[i], counts[i] = elem.Name, elem.Count
+		if len(elem.Properties) == 0 {
+			continue
+		}
+		counts[i] = PlyColumnsLength(elem.Properties)
+	}
+
+	version := doc.Version
+	plyfile, err := PlyOpenForWriting(filename, len(names), names, doc.Format, &version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// keep the file only if everything was written
+		if err != nil {
+			PlyAbort(plyfile)
+			return
+		}
+		err = PlyClose(plyfile)
+	}()
+
+	// describe the header
+	for _, comment := range doc.Comments {
+		PlyPutComment(plyfile, comment)
+	}
+	for _, obj_info := range doc.ObjInfo {
+		PlyPutObjInfo(plyfile, obj_info)
+	}
+	for i, elem := range doc.Elements {
+		for _, column := range elem.Properties {
+			if err = PlyDescribeProperty(plyfile, elem.Name, column.Prop); err != nil {
+				return err
+			}
+		}
+		if err = PlyElementCount(plyfile, elem.Name, counts[i]); err != nil {
+			return err
+		}
+	}
+	if err = PlyHeaderComplete(plyfile); err != nil {
+		return err
+	}
+
+	// write the data
+	for _, elem := range doc.Elements {
+		if err = PlyPutElementSetup(plyfile, elem.Name); err != nil {
+			return err
+		}
+		if err = PlyPutElementProperties(plyfile, elem.Properties); err != nil {
+			return fmt.Errorf("element %s: %w", elem.Name, err)
+		}
+	}
+	return nil
+}
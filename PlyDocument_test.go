@@ -0,0 +1,66 @@
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// a header using classic and sized type names, comments, obj_info, a list of doubles and an element without data
+const documentHeader = "comment made by hand\ncomment  two  spaces\nobj_info num_cols 640\nelement vertex 2\nproperty float32 x\nproperty float32 y\nproperty uchar confidence\nelement material 1\nproperty list int16 double params\nelement empty 0\nproperty int a\nend_header\n"
+
+func TestDocumentRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"ply\nformat ascii 1.0\n", []byte("0.1 -2 200\n1e+30 0 7\n2 3.5 -1e-300\n")},
+		{"ply\nformat binary_little_endian 1.0\n", leBytes(float32(0.1), float32(-2), uint8(200), float32(1e30), float32(0), uint8(7), int16(2), 3.5, -1e-300)},
+		{"ply\nformat binary_big_endian 1.25\n", beBytes(float32(0.1), float32(-2), uint8(200), float32(1e30), float32(0), uint8(7), int16(2), 3.5, -1e-300)},
+	}
+	for _, test := range tests {
+		t.Run(strings.Fields(test.name)[2], func(t *testing.T) {
+			filename := writeTestFile(t, "in.ply", test.name+documentHeader, test.data)
+			doc, err := PlyReadDocument(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			copied := filepath.Join(t.TempDir(), "copy.ply")
+			if err = PlyWriteDocument(copied, doc); err != nil {
+				t.Fatal(err)
+			}
+			before, _ := os.ReadFile(filename)
+			after, _ := os.ReadFile(copied)
+			if !bytes.Equal(before, after) {
+				t.Errorf("copy differs:\n%q\n%q", after, before)
+			}
+		})
+	}
+}
+
+func TestDocumentVersion(t *testing.T) {
+	doc := &PlyDocument{Format: PLY_ASCII, Elements: []PlyDocumentElement{{Name: "vertex", Properties: columns(PLY_FLOAT, []string{"x"}, []float64{1})}}}
+	for _, test := range []struct {
+		version float32
+		want    string
+	}{{0, "format ascii 1.0\n"}, {1, "format ascii 1.0\n"}, {1.25, "format ascii 1.25\n"}, {2, "format ascii 2.0\n"}} {
+		doc.Version = test.version
+		filename := filepath.Join(t.TempDir(), "version.ply")
+		if err := PlyWriteDocument(filename, doc); err != nil {
+			t.Fatal(err)
+		}
+		content, _ := os.ReadFile(filename)
+		if !strings.Contains(string(content), test.want) {
+			t.Errorf("version %g: header %q does not hold %q", test.version, content, test.want)
+		}
+		read, err := PlyReadDocument(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.version != 0 && read.Version != test.version {
+			t.Errorf("version read back %g, want %g", read.Version, test.version)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package plyReaderRealsense
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+/* PlyElementSize returns the number of bytes occupied in the data of the file by all the elements of a type. The size of binary elements without list follows from the types of their properties, the lengths of the lists (and the lines of ascii data) are otherwise read from the file once, without moving the reading position of the iterators. This needs a file opened by OpenPLY or PlyOpenReaderAt. */
+func PlyElementSize(plyfile *PlyFile, element_name string) (int64, error) {
+	index := plyElementIndex(plyfile, element_name)
+	if index < 0 {
+		return 0, fmt.Errorf("no element %s in the file", element_name)
+	}
+	_, size, err := plyElementSpan(plyfile, index)
+	return size, err
+}
+
+// plyRandomSource returns the source the data can be read from at any offset, nil for a stream or a compressed content
+func plyRandomSource(plyfile *PlyFile) io.ReaderAt {
+	if plyfile.compressed {
+		return nil
+	}
+	if plyfile.section != nil {
+		return plyfile.section
+	}
+	if plyfile.Fp != nil {
+		return plyfile.Fp
+	}
+	return nil
+}
+
+// plyElementSpan returns the offset in the file of the elements of the given index and their size in bytes, the sizes of the elements being measured once and kept
+func plyElementSpan(plyfile *PlyFile, index int) (int64, int64, error) {
+	if plyfile.sizes == nil {
+		plyfile.sizes = make([]int64, len(plyfile.elems))
+		for k := range plyfile.sizes {
+			plyfile.sizes[k] = -1
+		}
+	}
+
+	offset := int64(plyfile.header_vol)
+	for k := 0; k < index; k++ {
+		size, err := plyMeasureElement(plyfile, k, offset)
+		if err != nil {
+			return 0, 0, err
+		}
+		offset += size
+	}
+	size, err := plyMeasureElement(plyfile, index, offset)
+	return offset, size, err
+}
+
+// plyMeasureElement returns the size in bytes of the elements of the given index, starting at offset in the file
+func plyMeasureElement(plyfile *PlyFile, index int, offset int64) (int64, error) {
+	if plyfile.sizes[index] >= 0 {
+		return plyfile.sizes[index], nil
+	}
+	elem := &plyfile.elems[index]
+	if plyfile.file_type != PLY_ASCII {
+		if stride, fixed := PlyElementStride(elem.props); fixed {
+			plyfile.sizes[index] = int64(stride) * int64(elem.num)
+			return plyfile.sizes[index], nil
+		}
+	}
+
+	source := plyRandomSource(plyfile)
+	if source == nil {
+		return 0, fmt.Errorf("the size of element %s can only be known by reading it, which a stream does not allow", elem.name)
+	}
+	reader := bufio.NewReader(io.NewSectionReader(source, offset, math.MaxInt64-offset))
+	size, err := plySkipElements(plyfile, reader, elem.props, elem.num)
+	if err != nil {
+		return 0, plyDataError(err, elem.name)
+	}
+	plyfile.sizes[index] = size
+	return size, nil
+}
+
+// plySkipElements moves the reader past the next count elements having the given properties, and returns the number of bytes they occupy
+func plySkipElements(plyfile *PlyFile, reader *bufio.Reader, props []PlyProperty, count int) (int64, error) {
+	var size int64
+
+	// the ascii elements are the next non empty lines
+	if plyfile.file_type == PLY_ASCII {
+		for i := 0; i < count; {
+			line, err := reader.ReadString('\n')
+			size += int64(len(line))
+			if strings.TrimSpace(line) != "" {
+				i++
+				continue
+			}
+			if err == io.EOF {
+				return size, io.ErrUnexpectedEOF
+			}
+			if err != nil {
+				return size, err
+			}
+		}
+		return size, nil
+	}
+
+	for j := 0; j < len(props); j++ {
+		if PlyTypeSize(props[j].External_type) == 0 || (props[j].Is_list == 1 && PlyTypeSize(props[j].Count_external) == 0) {
+			return 0, errors.New("unknown type for property " + props[j].Name)
+		}
+	}
+	if stride, fixed := PlyElementStride(props); fixed {
+		n, err := reader.Discard(stride * count)
+		return int64(n), err
+	}
+
+	// the length of each list is read on the fly
+	order := plyByteOrder(plyfile)
+	for i := 0; i < count; i++ {
+		for j := 0; j < len(props); j++ {
+			skip := PlyTypeSize(props[j].External_type)
+			if props[j].Is_list == 1 {
+				head, err := reader.Peek(PlyTypeSize(props[j].Count_external))
+				if err != nil {
+					return size, err
+				}
+				num := int(plyDecodeScalar(head, props[j].Count_external, order))
+				if num < 0 {
+					return size, fmt.Errorf("negative length %d for list %s", num, props[j].Name)
+				}
+				skip = len(head) + num*skip
+			}
+			n, err := reader.Discard(skip)
+			size += int64(n)
+			if err != nil {
+				return size, err
+			}
+		}
+	}
+	return size, nil
+}
+
+// plySeekElement places the data reader at the start of the elements of the given index, anywhere in a file opened by OpenPLY or PlyOpenReaderAt
+func plySeekElement(plyfile *PlyFile, index int) error {
+	source := plyRandomSource(plyfile)
+	if source == nil {
+		return fmt.Errorf("element %s has already been read from the stream", plyfile.elems[index].name)
+	}
+	offset, _, err := plyElementSpan(plyfile, index)
+	if err != nil {
+		return err
+	}
+	plyfile.reader = bufio.NewReader(io.NewSectionReader(source, offset, math.MaxInt64-offset))
+	plyfile.cursor, plyfile.cursor_read = index, 0
+	return nil
+}
@@ -0,0 +1,113 @@
+package plyReaderRealsense
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// elements in an unusual order : a camera, edges of variable length, then the faces and the vertices
+const orderHeader = "element camera 1\nproperty double fx\nproperty uchar id\nelement edge 2\nproperty list char int v\nelement face 1\nproperty list uchar int vertex_indices\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\nend_header\n"
+
+// orderData encodes the elements of orderHeader, edgeCount being the length of the first edge list
+func orderData(edgeCount int8) []byte {
+	return leBytes(500.0, uint8(7),
+		int8(2), []int32{0, 1}, edgeCount, []int32{1, 2, 0},
+		uint8(3), []int32{0, 1, 2},
+		[]float32{1, 2, 3, 4, 5, 6, 7, 8, 9})
+}
+
+func TestElementSize(t *testing.T) {
+	filename := writeTestFile(t, "order.ply", "ply\nformat binary_little_endian 1.0\n"+orderHeader, orderData(3))
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+
+	for _, test := range []struct {
+		name string
+		size int64
+	}{{"camera", 9}, {"edge", 1 + 8 + 1 + 12}, {"face", 13}, {"vertex", 36}} {
+		size, err := PlyElementSize(plyfile, test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size != test.size {
+			t.Errorf("%s: size %d, want %d", test.name, size, test.size)
+		}
+	}
+}
+
+func TestReadInAnyOrder(t *testing.T) {
+	ascii := "0.5e3 7\n2 0 1\n3 1 2 0\n3 0 1 2\n1 2 3\n4 5 6\n7 8 9\n"
+	for _, file := range []struct {
+		format string
+		data   []byte
+	}{{"binary_little_endian", orderData(3)}, {"ascii", []byte(ascii)}} {
+		t.Run(file.format, func(t *testing.T) {
+			filename := writeTestFile(t, "order.ply", "ply\nformat "+file.format+" 1.0\n"+orderHeader, file.data)
+			plyfile, err := OpenPLY(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer PlyClose(plyfile)
+
+			// the vertices come last in the file and are read first, the camera is read again after them
+			for _, name := range []string{"vertex", "edge", "camera", "vertex"} {
+				data, err := PlyGetElementPropertiesE(plyfile, name)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				var got interface{}
+				var want interface{}
+				switch name {
+				case "vertex":
+					got, want = PlyFindProperty(data, "y").Values, []float64{2, 5, 8}
+				case "edge":
+					got, want = data[0].Lists, [][]float64{{0, 1}, {1, 2, 0}}
+				case "camera":
+					got, want = data[0].Values, []float64{500}
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestElementSizeMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"negative list", orderData(-3), "negative length"},
+		{"truncated list", orderData(3)[:20], ErrTruncated.Error()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := writeTestFile(t, "order.ply", "ply\nformat binary_little_endian 1.0\n"+orderHeader, test.data)
+			for _, read := range []func(*PlyFile) error{
+				func(p *PlyFile) error { _, err := PlyElementSize(p, "edge"); return err },
+				func(p *PlyFile) error { _, err := PlyGetElementPropertiesE(p, "vertex"); return err },
+				func(p *PlyFile) error { _, err := PlyGetElementPropertiesE(p, "edge"); return err },
+			} {
+				plyfile, err := OpenPLY(filename)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = read(plyfile)
+				PlyClose(plyfile)
+				if err == nil || !strings.Contains(err.Error(), test.want) {
+					t.Errorf("err = %v, want %s", err, test.want)
+				}
+				if test.want == ErrTruncated.Error() && !errors.Is(err, ErrTruncated) {
+					t.Errorf("err = %v is not ErrTruncated", err)
+				}
+			}
+		})
+	}
+}
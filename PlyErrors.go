@@ -0,0 +1,39 @@
+package plyReaderRealsense
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// errors returned by the functions reading and writing PLY files, to be compared with errors.Is
+var (
+	ErrNotPLY            = errors.New("not a PLY file")
+	ErrUnsupportedFormat = errors.New("unsupported PLY format")
+	ErrTruncated         = errors.New("truncated PLY file")
+	ErrBadHeader         = errors.New("bad PLY header")
+)
+
+// description of an error in the header, with the number of the line where it was found (starting from 1)
+type PlyHeaderError struct {
+	Line int
+	Msg  string
+}
+
+func (e *PlyHeaderError) Error() string {
+	return ErrBadHeader.Error() + " at line " + strconv.Itoa(e.Line) + ": " + e.Msg
+}
+
+// Unwrap makes errors.Is(err, ErrBadHeader) true for every header error
+func (e *PlyHeaderError) Unwrap() error {
+	return ErrBadHeader
+}
+
+// plyReadError returns ErrTruncated when a read stopped at the end of the data, the other errors (input/output, permission, decompression...) being wrapped with what was read
+func plyReadError(err error, what string) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	}
+	return fmt.Errorf("reading %s: %w", what, err)
+}
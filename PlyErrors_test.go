@@ -0,0 +1,106 @@
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const truncHeader = "ply\nformat binary_little_endian 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n"
+
+// errDisk stands for an input/output error of the device
+var errDisk = errors.New("input/output error")
+
+// gzipped compresses content
+func gzipped(content string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(content))
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestReadErrors(t *testing.T) {
+	vertices := string(leBytes(make([]float32, 6)))
+	corrupted := gzipped(truncHeader + vertices)
+	corrupted = append(corrupted[:len(corrupted)-8], 1, 2, 3, 4, 5, 6, 7, 8) // wrong checksum and size
+	tests := []struct {
+		name      string
+		input     io.Reader
+		truncated bool  // the error must be ErrTruncated
+		cause     error // else the error must wrap this one, if given
+	}{
+		{"truncated header", strings.NewReader("ply\nformat binary_little_endian 1.0\nelement vertex 2\n"), true, nil},
+		{"header i/o error", io.MultiReader(strings.NewReader("ply\nformat binary_"), iotest.ErrReader(errDisk)), false, errDisk},
+		{"truncated vertices", strings.NewReader(truncHeader + vertices[:10]), true, nil},
+		{"truncated faces", strings.NewReader(truncHeader + vertices + "\x03\x00\x00"), true, nil},
+		{"truncated list", strings.NewReader(truncHeader + vertices + string(leBytes(uint8(3)))), true, nil},
+		{"data i/o error", io.MultiReader(strings.NewReader(truncHeader+vertices[:10]), iotest.ErrReader(errDisk)), false, errDisk},
+		{"truncated gzip", bytes.NewReader(gzipped(truncHeader + vertices)[:60]), true, nil},
+		{"corrupted gzip", bytes.NewReader(corrupted), false, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := readAll(test.input)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if errors.Is(err, ErrTruncated) != test.truncated {
+				t.Errorf("err = %v, ErrTruncated expected: %t", err, test.truncated)
+			}
+			if test.cause != nil && !errors.Is(err, test.cause) {
+				t.Errorf("err = %v does not wrap %v", err, test.cause)
+			}
+		})
+	}
+}
+
+func TestGetElementHugeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     io.Reader
+		truncated bool
+	}{
+		{"binary truncated", strings.NewReader(truncHeader + "\x00\x00"), true},
+		{"binary i/o error", io.MultiReader(strings.NewReader(truncHeader+"\x00\x00"), iotest.ErrReader(errDisk)), false},
+		{"ascii truncated", strings.NewReader("ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n1 2 3\n4"), true},
+		{"ascii i/o error", io.MultiReader(strings.NewReader("ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n1 2 3\n"), iotest.ErrReader(errDisk)), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plyfile, err := PlyOpenReader(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vertices := make([]float32, 6)
+			err = PlyGetElementHugeE(plyfile, &vertices, 24)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if errors.Is(err, ErrTruncated) != test.truncated {
+				t.Errorf("err = %v, ErrTruncated expected: %t", err, test.truncated)
+			}
+			if !test.truncated && !errors.Is(err, errDisk) {
+				t.Errorf("err = %v does not wrap %v", err, errDisk)
+			}
+		})
+	}
+}
+
+// readAll decodes every element of the PLY data
+func readAll(r io.Reader) error {
+	plyfile, err := PlyOpenReader(r)
+	if err != nil {
+		return err
+	}
+	for _, name := range PlyGetElementNames(plyfile) {
+		if _, err = PlyGetElementPropertiesE(plyfile, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,21 +3,33 @@ package plyReaderRealsense
 import (
 	"bytes"
 	"encoding/binary"
+	"io"
+	"reflect"
 )
 
-/* PlyGetElementHuge retrieves all the elements of a type from the PLY file to reduce the time cost on calling system functions. */
+/* PlyGetElementHuge retrieves all the elements of a type from the PLY file to reduce the time cost on calling system functions. In ascii mode element is filled value after value whatever its line layout. */
 func PlyGetElementHuge(plyfile *PlyFile, element interface{}, size int) {
+	_ = PlyGetElementHugeE(plyfile, element, size)
+}
+
+/* PlyGetElementHugeE is PlyGetElementHuge returning the reading errors, ErrTruncated if the file holds less than size bytes, the other reading errors being wrapped */
+func PlyGetElementHugeE(plyfile *PlyFile, element interface{}, size int) error {
 	switch plyfile.file_type {
-	case PLY_BINARY_LE:
+	case PLY_BINARY_LE, PLY_BINARY_BE:
 		buffbytes := make([]byte, size)
 		r := bytes.NewReader(buffbytes)
-		_, _ = plyfile.Fp.Read(buffbytes)
-		_ = binary.Read(r, binary.LittleEndian, element)
+		if _, err := io.ReadFull(plyDataReader(plyfile), buffbytes); err != nil {
+			return plyReadError(err, "the data")
+		}
+		return binary.Read(r, plyByteOrder(plyfile), element)
 
-	case PLY_BINARY_BE:
-		buffbytes := make([]byte, size)
-		r := bytes.NewReader(buffbytes)
-		_, _ = plyfile.Fp.Read(buffbytes)
-		_ = binary.Read(r, binary.BigEndian, element)
+	case PLY_ASCII:
+		// the values are separated by whitespaces, size is meaningless here
+		err := plyReadAsciiValues(plyDataReader(plyfile), reflect.ValueOf(element))
+		if err != nil {
+			return plyReadError(err, "the data")
+		}
+		return nil
 	}
+	return ErrUnsupportedFormat
 }
\ No newline at end of file
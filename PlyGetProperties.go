@@ -0,0 +1,258 @@
+package plyReaderRealsense
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// decoded values of one property for all the elements of a type
+type PlyPropertyData struct {
+	Prop   PlyProperty // description of the property in the header
+	Values []float64   // scalar values, one for each element
+	Lists  [][]float64 // list values, one list for each element
+}
+
+/* PlyElementStride computes the offset of each property inside one element from its external type, and returns the size of one element in bytes. The second return is false if the element contains a list property, whose size is only known while reading : the offsets after the list are then set to -1. */
+func PlyElementStride(props []PlyProperty) (int, bool) {
+	stride := 0
+	fixed := true
+	for i := 0; i < len(props); i++ {
+		if !fixed {
+			props[i].Offset = -1
+			continue
+		}
+		props[i].Offset = stride
+		if props[i].Is_list == 1 {
+			fixed = false
+			continue
+		}
+		stride += PlyTypeSize(props[i].External_type)
+	}
+	return stride, fixed
+}
+
+/* PlyGetElementProperties reads all the elements of a type from the PLY file and decodes every property declared for it in the header. The elements placed before it are skipped, and an element already read is read again when the file can be read at random, so that the elements can be read in any order. */
+func PlyGetElementProperties(plyfile *PlyFile, element_name string) []PlyPropertyData {
+	data, _ := PlyGetElementPropertiesE(plyfile, element_name)
+	return data
+}
+
+/* PlyGetElementPropertiesE is PlyGetElementProperties returning the reading errors, ErrTruncated if the file ends before the last element */
+func PlyGetElementPropertiesE(plyfile *PlyFile, element_name string) ([]PlyPropertyData, error) {
+	data, err := plyGetElementProperties(plyfile, element_name)
+	return data, plyDataError(err, element_name)
+}
+
+/* PlyFindProperty returns the decoded values of the property with the given name, nil if the element does not have such a property */
+func PlyFindProperty(data []PlyPropertyData, name string) *PlyPropertyData {
+	for i := 0; i < len(data); i++ {
+		if data[i].Prop.Name == name {
+			return &data[i]
+		}
+	}
+	return nil
+}
+
+func plyGetElementProperties(plyfile *PlyFile, element_name string) ([]PlyPropertyData, error) {
+	props, num_elems, _ := PlyGetElementDescription(plyfile, element_name)
+	if plyElementIndex(plyfile, element_name) >= 0 {
+		if err := plySkipTo(plyfile, element_name); err != nil {
+			return nil, err
+		}
+	}
+
+	data := plyNewColumns(props, num_elems)
+	if err := plyDecodeElements(plyfile, props, data, num_elems); err != nil {
+		return data, err
+	}
+
+	// the file pointer is now at the start of the element following this one
+	if index := plyElementIndex(plyfile, element_name); index >= 0 {
+		plyfile.cursor, plyfile.cursor_read = index, 0
+		plyAdvance(plyfile, num_elems)
+	}
+	return data, nil
+}
+
+// plyNewColumns prepares one column able to hold n values for each property
+func plyNewColumns(props []PlyProperty, n int) []PlyPropertyData {
+	data := make([]PlyPropertyData, len(props))
+	for j := 0; j < len(props); j++ {
+		data[j].Prop = props[j]
+		if props[j].Is_list == 1 {
+			data[j].Lists = make([][]float64, n)
+		} else {
+			data[j].Values = make([]float64, n)
+		}
+	}
+	return data
+}
+
+// plyDecodeElements decodes the next count elements, having the given properties, into the first count values of the columns of data
+func plyDecodeElements(plyfile *PlyFile, props []PlyProperty, data []PlyPropertyData, count int) error {
+	reader := plyDataReader(plyfile)
+	if plyfile.file_type == PLY_ASCII {
+		return plyGetElementPropertiesAscii(reader, props, data, count)
+	}
+
+	// the binary layout can only be followed if the size of every type is known
+	for j := 0; j < len(props); j++ {
+		if PlyTypeSize(props[j].External_type) == 0 || (props[j].Is_list == 1 && PlyTypeSize(props[j].Count_external) == 0) {
+			return errors.New("unknown type for property " + props[j].Name)
+		}
+	}
+	order := plyByteOrder(plyfile)
+
+	// without any list the elements have a fixed size : read them in one block and decode each property at its offset
+	if stride, fixed := PlyElementStride(props); fixed {
+		block := make([]byte, stride*count)
+		if _, err := io.ReadFull(reader, block); err != nil {
+			return err
+		}
+		plyDecodeBlock(plyfile, props, data, block, stride, count)
+		return nil
+	}
+
+	// otherwise the length of each list is read on the fly to find where each element starts, then the elements are decoded
+	block, offsets, err := plyReadRecords(reader, props, count, order)
+	if err != nil {
+		return err
+	}
+	plyParallel(count, plyWorkers(plyfile), func(lo, hi int) {
+		// the lists of the range share one array, large enough for lists of the smallest type filling all the bytes
+		smallest := 8
+		for j := 0; j < len(props); j++ {
+			if props[j].Is_list == 1 && PlyTypeSize(props[j].External_type) < smallest {
+				smallest = PlyTypeSize(props[j].External_type)
+			}
+		}
+		values := make([]float64, 0, (offsets[hi]-offsets[lo])/smallest)
+
+		for i := lo; i < hi; i++ {
+			record := block[offsets[i]:offsets[i+1]]
+			for j := 0; j < len(props); j++ {
+				if props[j].Is_list == 0 {
+					data[j].Values[i] = plyDecodeScalar(record, props[j].External_type, order)
+					record = record[PlyTypeSize(props[j].External_type):]
+					continue
+				}
+
+				num := int(plyDecodeScalar(record, props[j].Count_external, order))
+				record = record[PlyTypeSize(props[j].Count_external):]
+				start := len(values)
+				for k := 0; k < num; k++ {
+					values = append(values, plyDecodeScalar(record, props[j].External_type, order))
+					record = record[PlyTypeSize(props[j].External_type):]
+				}
+				data[j].Lists[i] = values[start:len(values):len(values)]
+			}
+		}
+	})
+	return nil
+}
+
+// plyDecodeBlock decodes count elements of a fixed size, without any list, from a block of binary data
+func plyDecodeBlock(plyfile *PlyFile, props []PlyProperty, data []PlyPropertyData, block []byte, stride int, count int) {
+	order := plyByteOrder(plyfile)
+	plyParallel(count, plyWorkers(plyfile), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			record := block[i*stride : (i+1)*stride]
+			for j := 0; j < len(props); j++ {
+				data[j].Values[i] = plyDecodeScalar(record[props[j].Offset:], props[j].External_type, order)
+			}
+		}
+	})
+}
+
+// plyReadRecords copies the bytes of the next count elements, some of their properties being lists, and returns the offsets of the elements in the copy, the last offset being the end of the copy
+func plyReadRecords(reader io.Reader, props []PlyProperty, count int, order binary.ByteOrder) ([]byte, []int, error) {
+	block := make([]byte, 0, count*16)
+	offsets := make([]int, count+1)
+	var err error
+	for i := 0; i < count; i++ {
+		offsets[i] = len(block)
+		for j := 0; j < len(props); j++ {
+			size := PlyTypeSize(props[j].External_type)
+			if props[j].Is_list == 1 {
+				start := len(block)
+				if block, err = plyAppendRead(reader, block, PlyTypeSize(props[j].Count_external)); err != nil {
+					return block, offsets, err
+				}
+				num := int(plyDecodeScalar(block[start:], props[j].Count_external, order))
+				if num < 0 {
+					return block, offsets, fmt.Errorf("negative length %d for list %s", num, props[j].Name)
+				}
+				size *= num
+			}
+			if block, err = plyAppendRead(reader, block, size); err != nil {
+				return block, offsets, err
+			}
+		}
+	}
+	offsets[count] = len(block)
+	return block, offsets, nil
+}
+
+// plyAppendRead reads n bytes at the end of block
+func plyAppendRead(reader io.Reader, block []byte, n int) ([]byte, error) {
+	start := len(block)
+	if cap(block)-start < n {
+		grown := make([]byte, start, 2*cap(block)+n)
+		copy(grown, block)
+		block = grown
+	}
+	block = block[:start+n]
+	_, err := io.ReadFull(reader, block[start:])
+	return block, err
+}
+
+// plyDataError gives the context of an error met while reading the data of an element
+func plyDataError(err error, element_name string) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: in element %s", ErrTruncated, element_name)
+	}
+	if err != nil {
+		return fmt.Errorf("element %s: %w", element_name, err)
+	}
+	return nil
+}
+
+// byte order able to decode and to append encoded values
+type plyOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// plyByteOrder returns the byte order of the binary data in the file
+func plyByteOrder(plyfile *PlyFile) plyOrder {
+	if plyfile.file_type == PLY_BINARY_BE {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// plyDecodeScalar decodes one binary scalar of the given type at the start of b
+func plyDecodeScalar(b []byte, typ int, order binary.ByteOrder) float64 {
+	switch typ {
+	case PLY_CHAR:
+		return float64(int8(b[0]))
+	case PLY_UCHAR:
+		return float64(b[0])
+	case PLY_SHORT:
+		return float64(int16(order.Uint16(b)))
+	case PLY_USHORT:
+		return float64(order.Uint16(b))
+	case PLY_INT:
+		return float64(int32(order.Uint32(b)))
+	case PLY_UINT:
+		return float64(order.Uint32(b))
+	case PLY_FLOAT:
+		return float64(math.Float32frombits(order.Uint32(b)))
+	case PLY_DOUBLE:
+		return math.Float64frombits(order.Uint64(b))
+	}
+	return 0
+}
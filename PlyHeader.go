@@ -0,0 +1,163 @@
+package plyReaderRealsense
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// structured description of the header of a PLY file, with the sizes of its data
+type PlyHeader struct {
+	Format      string             `json:"format"` // ascii, binary_little_endian or binary_big_endian
+	Version     float32            `json:"version"`
+	Comments    []string           `json:"comments"`
+	ObjInfo     []string           `json:"obj_info"`
+	Elements    []PlyHeaderElement `json:"elements"`
+	HeaderSize  int64              `json:"header_size"`  // number of bytes of the header
+	DataSize    int64              `json:"data_size"`    // number of bytes of the data described by the header, -1 if the file is too short to hold them
+	FileSize    int64              `json:"file_size"`    // number of bytes of the file, -1 for a stream
+	SizeMatches bool               `json:"size_matches"` // the file holds the header and its data, nothing less and nothing more
+}
+
+// one type of element described by a PlyHeader
+type PlyHeaderElement struct {
+	Name       string              `json:"name"`
+	Count      int                 `json:"count"`
+	Size       int64               `json:"size"` // number of bytes of all the elements, -1 if unknown
+	Properties []PlyHeaderProperty `json:"properties"`
+}
+
+// one property of an element described by a PlyHeader, with the type names of the header
+type PlyHeaderProperty struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	List      bool   `json:"list,omitempty"`
+	CountType string `json:"count_type,omitempty"` // type of the length of a list
+}
+
+/* PlyInspect reads the header of a PLY file and measures its data, without decoding them. */
+func PlyInspect(filename string) (*PlyHeader, error) {
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer PlyClose(plyfile)
+
+	return PlyGetHeader(plyfile)
+}
+
+/* PlyGetHeader describes the header of an open PLY file. The sizes of the data are only measured for a file opened by OpenPLY or PlyOpenReaderAt, they are -1 for a stream. */
+func PlyGetHeader(plyfile *PlyFile) (*PlyHeader, error) {
+	header := &PlyHeader{
+		Format:     plyFormatName(plyfile.file_type),
+		Version:    plyfile.version,
+		Comments:   PlyGetComments(plyfile),
+		ObjInfo:    PlyGetObjInfo(plyfile),
+		HeaderSize: int64(plyfile.header_vol),
+		FileSize:   -1,
+	}
+
+	for _, name := range PlyGetElementNames(plyfile) {
+		props, num_elems, _ := PlyGetElementDescription(plyfile, name)
+		elem := PlyHeaderElement{Name: name, Count: num_elems, Size: -1}
+		for _, prop := range props {
+			property := PlyHeaderProperty{Name: prop.Name, Type: plyTypeName(plyfile, prop.External_type, prop.sized_type)}
+			if prop.Is_list == 1 {
+				property.List, property.CountType = true, plyTypeName(plyfile, prop.Count_external, prop.sized_count)
+			}
+			elem.Properties = append(elem.Properties, property)
+		}
+		header.Elements = append(header.Elements, elem)
+	}
+
+	// the sizes need the data to be read at random
+	source := plyRandomSource(plyfile)
+	if source == nil {
+		header.DataSize = -1
+		return header, nil
+	}
+	if plyfile.section != nil {
+		header.FileSize = plyfile.section.Size()
+	} else {
+		info, err := plyfile.Fp.Stat()
+		if err != nil {
+			return header, err
+		}
+		header.FileSize = info.Size()
+	}
+	for i := range header.Elements {
+		size, err := PlyElementSize(plyfile, header.Elements[i].Name)
+		if err != nil {
+			header.DataSize = -1
+			break
+		}
+		header.Elements[i].Size = size
+		header.DataSize += size
+	}
+	header.SizeMatches = header.DataSize >= 0 && header.HeaderSize+header.DataSize == header.FileSize
+	return header, nil
+}
+
+// plyFormatName returns the name of a file type in the format line of the header, "" for an unknown type
+func plyFormatName(file_type int) string {
+	switch file_type {
+	case PLY_ASCII:
+		return "ascii"
+	case PLY_BINARY_BE:
+		return "binary_big_endian"
+	case PLY_BINARY_LE:
+		return "binary_little_endian"
+	}
+	return ""
+}
+
+// plyFormatVersion writes a version number as in a header, with at least one decimal : 1.0, 1.25
+func plyFormatVersion(version float32) string {
+	if version == float32(int(version)) {
+		return strconv.FormatFloat(float64(version), 'f', 1, 32)
+	}
+	return strconv.FormatFloat(float64(version), 'f', -1, 32)
+}
+
+// String describes the header as the lines of a PLY header, followed by the sizes of the data
+func (header *PlyHeader) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "format %s %s\n", header.Format, plyFormatVersion(header.Version))
+	for _, comment := range header.Comments {
+		s.WriteString("comment " + comment + "\n")
+	}
+	for _, obj_info := range header.ObjInfo {
+		s.WriteString("obj_info " + obj_info + "\n")
+	}
+	for _, elem := range header.Elements {
+		fmt.Fprintf(&s, "element %s %d", elem.Name, elem.Count)
+		if elem.Size >= 0 {
+			fmt.Fprintf(&s, " (%d bytes)", elem.Size)
+		}
+		s.WriteString("\n")
+		for _, prop := range elem.Properties {
+			if prop.List {
+				fmt.Fprintf(&s, "  property list %s %s %s\n", prop.CountType, prop.Type, prop.Name)
+			} else {
+				fmt.Fprintf(&s, "  property %s %s\n", prop.Type, prop.Name)
+			}
+		}
+	}
+
+	fmt.Fprintf(&s, "header %d bytes", header.HeaderSize)
+	if header.DataSize >= 0 {
+		fmt.Fprintf(&s, ", data %d bytes", header.DataSize)
+	} else {
+		s.WriteString(", data of unknown size")
+	}
+	if header.FileSize >= 0 {
+		fmt.Fprintf(&s, ", file %d bytes", header.FileSize)
+		if header.SizeMatches {
+			s.WriteString(" : the size matches")
+		} else {
+			s.WriteString(" : the size does not match")
+		}
+	}
+	s.WriteString("\n")
+	return s.String()
+}
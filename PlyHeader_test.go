@@ -0,0 +1,83 @@
+package plyReaderRealsense
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 2 vertices of 12 bytes and a face of 13 bytes
+const sizedHeader = "ply\nformat binary_little_endian 1.0\ncomment made by hand\nobj_info num_cols 640\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n"
+
+func sizedData() []byte {
+	return leBytes(float32(1), float32(2), float32(3), float32(4), float32(5), float32(6), uint8(3), int32(0), int32(1), int32(1))
+}
+
+func TestPlyInspectSizes(t *testing.T) {
+	data := sizedData()
+	header_size := int64(len(sizedHeader))
+	tests := []struct {
+		name      string
+		filename  string
+		content   []byte
+		data_size int64
+		file_size int64
+		matches   bool
+	}{
+		{"matching", "ok.ply", data, 37, header_size + 37, true},
+		{"truncated in the vertices", "short.ply", data[:20], -1, header_size + 20, false},
+		{"truncated in the face", "short.ply", data[:30], -1, header_size + 30, false},
+		{"oversized", "long.ply", append(append([]byte{}, data...), 0, 0), 37, header_size + 39, false},
+		{"compressed", "ok.ply.gz", gzipped(sizedHeader + string(data)), -1, -1, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), test.filename)
+			content := test.content
+			if filepath.Ext(test.filename) != ".gz" {
+				content = append([]byte(sizedHeader), content...)
+			}
+			if err := os.WriteFile(filename, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			header, err := PlyInspect(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header.HeaderSize != header_size || header.DataSize != test.data_size || header.FileSize != test.file_size || header.SizeMatches != test.matches {
+				t.Errorf("header %d data %d file %d matches %v, want %d %d %d %v", header.HeaderSize, header.DataSize, header.FileSize, header.SizeMatches, header_size, test.data_size, test.file_size, test.matches)
+			}
+			if header.Format != "binary_little_endian" || len(header.Elements) != 2 || header.Elements[1].Properties[0].CountType != "uchar" {
+				t.Errorf("header %+v", header)
+			}
+		})
+	}
+}
+
+func TestPlyHeaderString(t *testing.T) {
+	filename := writeTestFile(t, "ok.ply", sizedHeader, sizedData())
+	header, err := PlyInspect(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "format binary_little_endian 1.0\ncomment made by hand\nobj_info num_cols 640\n" +
+		"element vertex 2 (24 bytes)\n  property float x\n  property float y\n  property float z\n" +
+		"element face 1 (13 bytes)\n  property list uchar int vertex_indices\n" +
+		"header 212 bytes, data 37 bytes, file 249 bytes : the size matches\n"
+	if s := header.String(); s != want {
+		t.Errorf("String() =\n%s\nwant\n%s", s, want)
+	}
+
+	// the sizes of a stream are unknown, the sized type names of the header are kept
+	header.DataSize, header.FileSize, header.SizeMatches = -1, -1, false
+	header.Elements[0].Size, header.Elements[1].Size = -1, -1
+	header.Version = 1.25
+	header.Elements[0].Properties[0].Type = "float32"
+	want = "format binary_little_endian 1.25\ncomment made by hand\nobj_info num_cols 640\n" +
+		"element vertex 2\n  property float32 x\n  property float y\n  property float z\n" +
+		"element face 1\n  property list uchar int vertex_indices\n" +
+		"header 212 bytes, data of unknown size\n"
+	if s := header.String(); s != want {
+		t.Errorf("String() =\n%s\nwant\n%s", s, want)
+	}
+}
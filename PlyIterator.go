@@ -0,0 +1,323 @@
+package plyReaderRealsense
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// number of elements decoded at once by the iterators
+const PLY_CHUNK_SIZE = 4096
+
+// names under which the list of vertex indices of a face is found
+var plyFaceIndicesNames = []string{"vertex_indices", "vertex_index"}
+
+// names under which the color components of a vertex are found
+var (
+	plyRedNames   = []string{"red", "r", "diffuse_red"}
+	plyGreenNames = []string{"green", "g", "diffuse_green"}
+	plyBlueNames  = []string{"blue", "b", "diffuse_blue"}
+	plyAlphaNames = []string{"alpha", "a", "diffuse_alpha"}
+	plyNXNames    = []string{"nx", "normal_x"}
+	plyNYNames    = []string{"ny", "normal_y"}
+	plyNZNames    = []string{"nz", "normal_z"}
+	plyUNames     = []string{"u", "s", "texture_u", "texture_s"}
+	plyVNames     = []string{"v", "t", "texture_v", "texture_t"}
+)
+
+// common part of the iterators : the elements of a type are decoded chunk after chunk into columns reused for every chunk
+type elementIterator struct {
+	plyfile   *PlyFile
+	name      string
+	props     []PlyProperty
+	data      []PlyPropertyData
+	remaining int // number of elements not decoded yet
+	chunk     int // number of elements decoded at once
+	n         int // number of elements in the current chunk
+	i         int // index of the current element in the chunk
+	err       error
+}
+
+// iterator over the vertices of an open PLY file
+type VertexIterator struct {
+	elementIterator
+	x, y, z    *PlyPropertyData
+	r, g, b, a *PlyPropertyData // nil when the vertices have no color or no alpha
+	u, v       *PlyPropertyData // nil when the vertices have no texture coordinates
+	nx, ny, nz *PlyPropertyData // nil when the vertices have no normal
+}
+
+// iterator over the faces of an open PLY file
+type FaceIterator struct {
+	elementIterator
+	indices *PlyPropertyData
+}
+
+/* Vertices returns an iterator decoding the vertices of the file in chunks of PLY_CHUNK_SIZE elements for each decoding goroutine, so that the whole cloud never has to be held in memory : it.Next() moves to the next vertex, it.Vertex() returns it and it.Err() tells why the iteration stopped. The elements placed before the vertices in the file are skipped. */
+func (plyfile *PlyFile) Vertices() *VertexIterator {
+	it := &VertexIterator{}
+	it.init(plyfile, "vertex")
+	if it.err == nil {
+		it.bind()
+	}
+	return it
+}
+
+// bind finds the columns of the properties of the vertices
+func (it *VertexIterator) bind() {
+	it.x, it.y, it.z = PlyFindProperty(it.data, "x"), PlyFindProperty(it.data, "y"), PlyFindProperty(it.data, "z")
+	if it.x == nil || it.y == nil || it.z == nil {
+		it.err = errors.New("vertex without x, y and z properties")
+	}
+	it.r, it.g, it.b = plyFindScalar(it.data, plyRedNames), plyFindScalar(it.data, plyGreenNames), plyFindScalar(it.data, plyBlueNames)
+	it.a = plyFindScalar(it.data, plyAlphaNames)
+	it.u, it.v = plyFindScalar(it.data, plyUNames), plyFindScalar(it.data, plyVNames)
+	it.nx, it.ny, it.nz = plyFindScalar(it.data, plyNXNames), plyFindScalar(it.data, plyNYNames), plyFindScalar(it.data, plyNZNames)
+}
+
+/* Faces returns an iterator decoding the faces of the file in chunks of PLY_CHUNK_SIZE elements for each decoding goroutine, the elements placed before the faces in the file are skipped. */
+func (plyfile *PlyFile) Faces() *FaceIterator {
+	it := &FaceIterator{}
+	it.init(plyfile, "face")
+	if it.err == nil {
+		for _, name := range plyFaceIndicesNames {
+			if it.indices = PlyFindProperty(it.data, name); it.indices != nil && it.indices.Prop.Is_list == 1 {
+				break
+			}
+			it.indices = nil
+		}
+		if it.indices == nil {
+			it.err = errors.New("face without vertex_indices property")
+		}
+	}
+	return it
+}
+
+// Vertex returns the current vertex
+func (it *VertexIterator) Vertex() VertexMono {
+	return VertexMono{float32(it.x.Values[it.i]), float32(it.y.Values[it.i]), float32(it.z.Values[it.i])}
+}
+
+// Vertex64 returns the current vertex in 64 bits
+func (it *VertexIterator) Vertex64() VertexMono64 {
+	return VertexMono64{it.x.Values[it.i], it.y.Values[it.i], it.z.Values[it.i]}
+}
+
+// HasColor tells whether the vertices have red, green and blue properties
+func (it *VertexIterator) HasColor() bool {
+	return it.r != nil && it.g != nil && it.b != nil
+}
+
+// HasAlpha tells whether the vertices have an alpha property
+func (it *VertexIterator) HasAlpha() bool {
+	return it.a != nil
+}
+
+// VertexColor returns the current vertex with its color, black if the vertices have no color
+func (it *VertexIterator) VertexColor() Vertex {
+	v := Vertex{X: float32(it.x.Values[it.i]), Y: float32(it.y.Values[it.i]), Z: float32(it.z.Values[it.i])}
+	if it.HasColor() {
+		v.R, v.G, v.B = plyColorByte(it.r, it.i), plyColorByte(it.g, it.i), plyColorByte(it.b, it.i)
+	}
+	return v
+}
+
+// VertexColor64 returns the current vertex with its color in 64 bits, black if the vertices have no color
+func (it *VertexIterator) VertexColor64() Vertex64 {
+	v := Vertex64{X: it.x.Values[it.i], Y: it.y.Values[it.i], Z: it.z.Values[it.i]}
+	if it.HasColor() {
+		v.R, v.G, v.B = plyColorByte(it.r, it.i), plyColorByte(it.g, it.i), plyColorByte(it.b, it.i)
+	}
+	return v
+}
+
+// Alpha returns the opacity of the current vertex, 255 if the vertices have no alpha
+func (it *VertexIterator) Alpha() uint8 {
+	if it.a == nil {
+		return 255
+	}
+	return plyColorByte(it.a, it.i)
+}
+
+// HasNormal tells whether the vertices have nx, ny and nz properties
+func (it *VertexIterator) HasNormal() bool {
+	return it.nx != nil && it.ny != nil && it.nz != nil
+}
+
+// VertexNormal returns the current vertex with its normal, a zero normal if the vertices have none
+func (it *VertexIterator) VertexNormal() VertexNormal {
+	v := VertexNormal{X: float32(it.x.Values[it.i]), Y: float32(it.y.Values[it.i]), Z: float32(it.z.Values[it.i])}
+	if it.HasNormal() {
+		v.NX, v.NY, v.NZ = float32(it.nx.Values[it.i]), float32(it.ny.Values[it.i]), float32(it.nz.Values[it.i])
+	}
+	return v
+}
+
+// VertexNormal64 returns the current vertex with its normal in 64 bits, a zero normal if the vertices have none
+func (it *VertexIterator) VertexNormal64() VertexNormal64 {
+	v := VertexNormal64{X: it.x.Values[it.i], Y: it.y.Values[it.i], Z: it.z.Values[it.i]}
+	if it.HasNormal() {
+		v.NX, v.NY, v.NZ = it.nx.Values[it.i], it.ny.Values[it.i], it.nz.Values[it.i]
+	}
+	return v
+}
+
+// HasUV tells whether the vertices have texture coordinates
+func (it *VertexIterator) HasUV() bool {
+	return it.u != nil && it.v != nil
+}
+
+// UV returns the texture coordinates of the current vertex, zero if the vertices have none
+func (it *VertexIterator) UV() TexCoord {
+	if !it.HasUV() {
+		return TexCoord{}
+	}
+	return TexCoord{float32(it.u.Values[it.i]), float32(it.v.Values[it.i])}
+}
+
+// UV64 returns the texture coordinates of the current vertex in 64 bits, zero if the vertices have none
+func (it *VertexIterator) UV64() TexCoord64 {
+	if !it.HasUV() {
+		return TexCoord64{}
+	}
+	return TexCoord64{it.u.Values[it.i], it.v.Values[it.i]}
+}
+
+// Face returns the first 3 vertex indices of the current face
+func (it *FaceIterator) Face() Face32 {
+	list := it.indices.Lists[it.i]
+	if len(list) < 3 {
+		return Face32{}
+	}
+	return Face32{int32(list[0]), int32(list[1]), int32(list[2])}
+}
+
+// Face64 returns the first 3 vertex indices of the current face in 64 bits
+func (it *FaceIterator) Face64() Face64 {
+	list := it.indices.Lists[it.i]
+	if len(list) < 3 {
+		return Face64{}
+	}
+	return Face64{int64(list[0]), int64(list[1]), int64(list[2])}
+}
+
+// Polygon returns all the vertex indices of the current face
+func (it *FaceIterator) Polygon() Polygon32 {
+	list := it.indices.Lists[it.i]
+	polygon := make(Polygon32, len(list))
+	for k := range list {
+		polygon[k] = int32(list[k])
+	}
+	return polygon
+}
+
+// Polygon64 returns all the vertex indices of the current face in 64 bits
+func (it *FaceIterator) Polygon64() Polygon64 {
+	list := it.indices.Lists[it.i]
+	polygon := make(Polygon64, len(list))
+	for k := range list {
+		polygon[k] = int64(list[k])
+	}
+	return polygon
+}
+
+// Indices returns all the vertex indices of the current face, the slice is only valid until the next call to Next
+func (it *FaceIterator) Indices() []float64 {
+	return it.indices.Lists[it.i]
+}
+
+// plyFindScalar returns the first scalar property found under one of the names
+func plyFindScalar(data []PlyPropertyData, names []string) *PlyPropertyData {
+	for _, name := range names {
+		if prop := PlyFindProperty(data, name); prop != nil && prop.Prop.Is_list == 0 {
+			return prop
+		}
+	}
+	return nil
+}
+
+// plyColorByte returns a color component between 0 and 255, the components stored as float or double being between 0 and 1
+func plyColorByte(prop *PlyPropertyData, i int) uint8 {
+	value := prop.Values[i]
+	if prop.Prop.External_type == PLY_FLOAT || prop.Prop.External_type == PLY_DOUBLE {
+		value = math.Round(value * 255)
+	}
+	return uint8(math.Max(0, math.Min(255, value)))
+}
+
+// Next decodes the next element, it returns false at the end of the elements or after an error
+func (it *elementIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.i++
+	if it.i < it.n {
+		return true
+	}
+	if it.remaining == 0 {
+		return false
+	}
+
+	// decode the next chunk
+	it.n = it.chunk
+	if it.remaining < it.n {
+		it.n = it.remaining
+	}
+	if err := plyDecodeElements(it.plyfile, it.props, it.data, it.n); err != nil {
+		it.err = plyDataError(err, it.name)
+		return false
+	}
+	plyAdvance(it.plyfile, it.n)
+	it.remaining -= it.n
+	it.i = 0
+	return true
+}
+
+// Err returns the error which stopped the iteration, nil if all the elements have been read
+func (it *elementIterator) Err() error {
+	return it.err
+}
+
+// init places the data reader at the start of the element and prepares the columns of one chunk
+func (it *elementIterator) init(plyfile *PlyFile, element_name string) {
+	it.plyfile, it.name, it.i = plyfile, element_name, -1
+	props, num_elems, _ := PlyGetElementDescription(plyfile, element_name)
+	if plyElementIndex(plyfile, element_name) < 0 {
+		it.err = fmt.Errorf("no element %s in the file", element_name)
+		return
+	}
+	if it.err = plySkipTo(plyfile, element_name); it.err != nil {
+		return
+	}
+
+	// each goroutine decoding the binary data gets a whole chunk
+	chunk := PLY_CHUNK_SIZE
+	if plyfile.file_type != PLY_ASCII {
+		chunk *= plyWorkers(plyfile)
+	}
+	if num_elems < chunk {
+		chunk = num_elems
+	}
+	it.props, it.remaining, it.chunk = props, num_elems, chunk
+	it.data = plyNewColumns(props, chunk)
+}
+
+// plySkipTo places the data reader at the start of the given element : the elements between the cursor and this element are skipped, an element already passed is found again from its offset when the data can be read at random
+func plySkipTo(plyfile *PlyFile, element_name string) error {
+	index := plyElementIndex(plyfile, element_name)
+	if index < 0 {
+		return fmt.Errorf("no element %s in the file", element_name)
+	}
+	if plyfile.cursor > index || (plyfile.cursor == index && plyfile.cursor_read > 0) {
+		return plySeekElement(plyfile, index)
+	}
+
+	for plyfile.cursor < index {
+		elem := &plyfile.elems[plyfile.cursor]
+		if _, err := plySkipElements(plyfile, plyDataReader(plyfile), elem.props, elem.num-plyfile.cursor_read); err != nil {
+			return plyDataError(err, elem.name)
+		}
+		plyAdvance(plyfile, elem.num-plyfile.cursor_read)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package plyReaderRealsense
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// size of a face made of a uchar count and 3 int indices in a binary file
+const PLY_FACE_SIZE = 13
+
+// a binary little endian PLY file mapped in memory, the slices are views on a private copy on write mapping of the file and must not be used after Close
+type PlyMapped struct {
+	Vertices []VertexMono // x, y, z of each vertex, as stored in the file
+	Faces    MappedFaces  // faces as stored in the file
+	plyfile  *PlyFile
+	data     []byte // the whole mapped file
+}
+
+// the face block of a mapped file : packed records of a uchar count followed by 3 int indices
+type MappedFaces []byte
+
+/* File returns the header of the mapped file, to be used with PlyGetElementDescription, PlyGetComments... */
+func (m *PlyMapped) File() *PlyFile {
+	return m.plyfile
+}
+
+// Len returns the number of faces
+func (f MappedFaces) Len() int {
+	return len(f) / PLY_FACE_SIZE
+}
+
+// At decodes the indices of the face i
+func (f MappedFaces) At(i int) Face32 {
+	record := f[i*PLY_FACE_SIZE : (i+1)*PLY_FACE_SIZE]
+	return Face32{int32(binary.LittleEndian.Uint32(record[1:])), int32(binary.LittleEndian.Uint32(record[5:])), int32(binary.LittleEndian.Uint32(record[9:]))}
+}
+
+// locate parses the header and finds the vertex and face blocks in the mapped file
+func (m *PlyMapped) locate() error {
+	plyfile, err := plyReadHeader(bufio.NewReader(bytes.NewReader(m.data)))
+	if err != nil {
+		return err
+	}
+	m.plyfile = plyfile
+
+	// the data are only usable as they are if the machine is little endian too
+	host := [2]byte{1, 0}
+	if plyfile.file_type != PLY_BINARY_LE || *(*uint16)(unsafe.Pointer(&host)) != 1 {
+		return ErrUnsupportedFormat
+	}
+
+	// walk the elements to find the start of each block
+	offset := plyfile.header_vol
+	for _, elem := range plyfile.elems {
+		if elem.num < 0 {
+			return fmt.Errorf("negative count %d for element %s", elem.num, elem.name)
+		}
+		var size int
+		switch {
+		case elem.name == "vertex" && plyMappableVertex(elem.props):
+			if elem.num > (len(m.data)-offset)/12 {
+				return ErrTruncated
+			}
+			size = elem.num * 12
+			m.Vertices = plyViewVertices(m.data[offset:offset+size], elem.num)
+
+		case elem.name == "face" && plyMappableFace(elem.props):
+			if elem.num > (len(m.data)-offset)/PLY_FACE_SIZE {
+				return ErrTruncated
+			}
+			size = elem.num * PLY_FACE_SIZE
+			m.Faces = MappedFaces(m.data[offset : offset+size])
+			for i := 0; i < size; i += PLY_FACE_SIZE {
+				if m.Faces[i] != 3 {
+					return errors.New("mapped faces must all have 3 vertices")
+				}
+			}
+
+		case elem.name == "vertex" || elem.name == "face":
+			return errors.New("layout of element " + elem.name + " can not be mapped")
+
+		default:
+			// other elements are only skipped, their lists are read in the mapped memory
+			size, err = plyMappedElementSize(m.data[offset:], elem)
+			if err != nil {
+				return err
+			}
+		}
+		offset += size
+	}
+	return nil
+}
+
+// plyMappableVertex tells whether the vertices are made of the float x, y and z only
+func plyMappableVertex(props []PlyProperty) bool {
+	if len(props) != 3 {
+		return false
+	}
+	for i, name := range []string{"x", "y", "z"} {
+		if props[i].Name != name || props[i].Is_list == 1 || props[i].External_type != PLY_FLOAT {
+			return false
+		}
+	}
+	return true
+}
+
+// plyMappableFace tells whether the faces are made of a list of int indices with a uchar count only
+func plyMappableFace(props []PlyProperty) bool {
+	return len(props) == 1 && props[0].Is_list == 1 && props[0].Count_external == PLY_UCHAR && (props[0].External_type == PLY_INT || props[0].External_type == PLY_UINT)
+}
+
+// plyViewVertices returns the vertices held in block, without copying them when the architecture allows it
+func plyViewVertices(block []byte, num int) []VertexMono {
+	if num == 0 {
+		return []VertexMono{}
+	}
+	if uintptr(unsafe.Pointer(&block[0]))%unsafe.Alignof(float32(0)) == 0 || plyUnalignedOK() {
+		return unsafe.Slice((*VertexMono)(unsafe.Pointer(&block[0])), num)
+	}
+	vertices := make([]VertexMono, num)
+	_ = binary.Read(bytes.NewReader(block), binary.LittleEndian, vertices)
+	return vertices
+}
+
+// plyUnalignedOK tells whether the architecture reads unaligned floats
+func plyUnalignedOK() bool {
+	switch runtime.GOARCH {
+	case "386", "amd64", "arm64", "ppc64le", "ppc64", "s390x":
+		return true
+	}
+	return false
+}
+
+// plyMappedElementSize computes the number of bytes occupied by the elements of a type at the start of block
+func plyMappedElementSize(block []byte, elem PlyElement) (int, error) {
+	for _, prop := range elem.props {
+		if PlyTypeSize(prop.External_type) == 0 || (prop.Is_list == 1 && PlyTypeSize(prop.Count_external) == 0) {
+			return 0, errors.New("unknown type for property " + prop.Name)
+		}
+	}
+
+	if stride, fixed := PlyElementStride(elem.props); fixed {
+		// compare by division so that a huge count can not overflow
+		if stride > 0 && elem.num > len(block)/stride {
+			return 0, ErrTruncated
+		}
+		return elem.num * stride, nil
+	}
+
+	offset := 0
+	for i := 0; i < elem.num; i++ {
+		for _, prop := range elem.props {
+			if prop.Is_list == 0 {
+				offset += PlyTypeSize(prop.External_type)
+				continue
+			}
+			size := PlyTypeSize(prop.Count_external)
+			if offset+size > len(block) {
+				return 0, ErrTruncated
+			}
+			count := int(plyDecodeScalar(block[offset:], prop.Count_external, binary.LittleEndian))
+			if count < 0 {
+				return 0, fmt.Errorf("negative length %d for list %s", count, prop.Name)
+			}
+			offset += size
+			if count > (len(block)-offset)/PlyTypeSize(prop.External_type) {
+				return 0, ErrTruncated
+			}
+			offset += count * PlyTypeSize(prop.External_type)
+		}
+		if offset > len(block) {
+			return 0, ErrTruncated
+		}
+	}
+	return offset, nil
+}
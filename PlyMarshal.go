@@ -0,0 +1,321 @@
+package plyReaderRealsense
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// description of a field of a struct bound to a property
+type plyField struct {
+	index []int  // index of the field in the struct
+	name  string // name of the property
+	list  bool   // the field is a slice or an array holding a list property
+	typ   int    // type of the values given in the tag, PLY_START_TYPE if not given
+	count int    // type of the length of a list given in the tag, PLY_START_TYPE if not given
+}
+
+/* Unmarshal reads a PLY file into slices of structs, one for each element to read, as in Unmarshal("cloud.ply", map[string]any{"vertex": &vertices, "face": &faces}). The fields of the structs are matched to the properties of the header by their tag, `ply:"x"` or `ply:"vertex_indices,list"`, or else by their name in lower case, `ply:"-"` ignoring a field. The values are converted to the types of the fields, list properties going into slices or arrays. Properties without field and fields without property are left aside, as are the elements absent from the map. */
+func Unmarshal(filename string, elements map[string]any) error {
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		return err
+	}
+	defer PlyClose(plyfile)
+
+	// read the elements in the order of the file
+	for _, name := range PlyGetElementNames(plyfile) {
+		target, ok := elements[name]
+		if !ok {
+			// skipped without being decoded
+			continue
+		}
+		data, err := PlyGetElementPropertiesE(plyfile, name)
+		if err != nil {
+			return err
+		}
+		_, num_elems, _ := PlyGetElementDescription(plyfile, name)
+		if err = plyUnmarshalElement(data, num_elems, target); err != nil {
+			return fmt.Errorf("element %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+/* Marshal writes slices of structs as the elements of a PLY file in the given format, as in Marshal("cloud.ply", PLY_BINARY_LE, map[string]any{"vertex": vertices, "face": faces}). The properties are named by the tags of the fields as in Unmarshal, their types are given by the tag, `ply:"x,double"`, or else by the Go type of the fields (float32 as float, uint8 as uchar, int64 and int as int...). The length of a list is written in the type following "list" in the tag, `ply:"vertex_indices,list,uint"`, or else in the smallest of uchar, ushort and uint holding the longest list. An error is returned, before the file is created, for a value out of the range of its type or a list too long for its length type. The vertices are written first, then the faces, then the other elements in the order of their names. */
+func Marshal(filename string, file_type int, elements map[string]any) (err error) {
+	// the order of the elements in the file
+	var names []string
+	for name := range elements {
+		if name != "vertex" && name != "face" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range []string{"face", "vertex"} {
+		if _, ok := elements[name]; ok {
+			names = append([]string{name}, names...)
+		}
+	}
+
+	// turn the slices into columns
+	columns := make([][]PlyPropertyData, len(names))
+	for i, name := range names {
+		if columns[i], err = plyMarshalElement(elements[name]); err != nil {
+			return fmt.Errorf("element %s: %w", name, err)
+		}
+	}
+
+	version := float32(1)
+	plyfile, err := PlyOpenForWriting(filename, len(names), names, file_type, &version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// keep the file only if everything was written
+		if err != nil {
+			PlyAbort(plyfile)
+			return
+		}
+		err = PlyClose(plyfile)
+	}()
+
+	// describe the header
+	for i, name := range names {
+		for _, column := range columns[i] {
+			if err = PlyDescribeProperty(plyfile, name, column.Prop); err != nil {
+				return err
+			}
+		}
+		if err = PlyElementCount(plyfile, name, reflect.Indirect(reflect.ValueOf(elements[name])).Len()); err != nil {
+			return err
+		}
+	}
+	if err = PlyHeaderComplete(plyfile); err != nil {
+		return err
+	}
+
+	// write the data
+	for i, name := range names {
+		if err = PlyPutElementSetup(plyfile, name); err != nil {
+			return err
+		}
+		if err = PlyPutElementProperties(plyfile, columns[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plyUnmarshalElement fills the slice of structs pointed by target with the decoded columns
+func plyUnmarshalElement(data []PlyPropertyData, num_elems int, target any) error {
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Ptr || pointer.Elem().Kind() != reflect.Slice || pointer.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%T is not a pointer to a slice of structs", target)
+	}
+	fields, err := plyStructFields(pointer.Elem().Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	slice := reflect.MakeSlice(pointer.Elem().Type(), num_elems, num_elems)
+	for _, field := range fields {
+		prop := PlyFindProperty(data, field.name)
+		if prop == nil {
+			continue
+		}
+		if field.list != (prop.Prop.Is_list == 1) {
+			return fmt.Errorf("property %s and its field are not both lists", field.name)
+		}
+
+		for i := 0; i < num_elems; i++ {
+			value := slice.Index(i).FieldByIndex(field.index)
+			if !field.list {
+				plySetNumber(value, prop.Values[i])
+				continue
+			}
+			list := prop.Lists[i]
+			if value.Kind() == reflect.Slice {
+				value.Set(reflect.MakeSlice(value.Type(), len(list), len(list)))
+			}
+			for k := 0; k < len(list) && k < value.Len(); k++ {
+				plySetNumber(value.Index(k), list[k])
+			}
+		}
+	}
+	pointer.Elem().Set(slice)
+	return nil
+}
+
+// plyMarshalElement turns a slice of structs, or a pointer to it, into the columns of its properties
+func plyMarshalElement(source any) ([]PlyPropertyData, error) {
+	slice := reflect.Indirect(reflect.ValueOf(source))
+	if slice.Kind() != reflect.Slice || slice.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T is not a slice of structs", source)
+	}
+	fields, err := plyStructFields(slice.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]PlyPropertyData, len(fields))
+	for j, field := range fields {
+		goType := slice.Type().Elem().FieldByIndex(field.index).Type
+		if field.list {
+			goType = goType.Elem()
+		}
+		typ := field.typ
+		if typ == PLY_START_TYPE {
+			typ = plyGoType(goType.Kind())
+		}
+		if typ == PLY_START_TYPE {
+			return nil, fmt.Errorf("no PLY type for field %s of type %s", field.name, goType)
+		}
+
+		if !field.list {
+			data[j].Prop = *New_property(field.name, typ, typ, 0, 0, 0, 0, 0)
+			data[j].Values = make([]float64, slice.Len())
+			for i := range data[j].Values {
+				data[j].Values[i] = plyGetNumber(slice.Index(i).FieldByIndex(field.index))
+				if err = plyCheckValue(typ, data[j].Values[i], field.name); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		data[j].Lists = make([][]float64, slice.Len())
+		longest := 0
+		for i := range data[j].Lists {
+			value := slice.Index(i).FieldByIndex(field.index)
+			list := make([]float64, value.Len())
+			for k := range list {
+				list[k] = plyGetNumber(value.Index(k))
+			}
+			data[j].Lists[i] = list
+			if len(list) > longest {
+				longest = len(list)
+			}
+		}
+		count := field.count
+		if count == PLY_START_TYPE {
+			count = plyCountType(longest)
+		}
+		data[j].Prop = *New_property(field.name, typ, typ, 0, 1, count, count, 0)
+		for _, list := range data[j].Lists {
+			if err = plyCheckList(data[j].Prop, list); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return data, nil
+}
+
+// plyCountType returns the smallest unsigned type holding the length of a list
+func plyCountType(length int) int {
+	switch {
+	case length <= math.MaxUint8:
+		return PLY_UCHAR
+	case length <= math.MaxUint16:
+		return PLY_USHORT
+	}
+	return PLY_UINT
+}
+
+// plyStructFields lists the exported fields of a struct with the properties they are bound to
+func plyStructFields(t reflect.Type) ([]plyField, error) {
+	var fields []plyField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("ply")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+
+		field := plyField{index: f.Index, name: strings.ToLower(f.Name)}
+		kind := f.Type.Kind()
+		field.list = kind == reflect.Slice || kind == reflect.Array
+		options := strings.Split(tag, ",")
+		if options[0] != "" {
+			field.name = options[0]
+		}
+		for i, option := range options[1:] {
+			switch {
+			case option == "list":
+				if !field.list {
+					return nil, fmt.Errorf("field %s tagged as a list is not a slice nor an array", f.Name)
+				}
+			case TypeConverter(option) != 0 && options[i] == "list":
+				// the type following "list" is the one of the length
+				field.count = TypeConverter(option)
+				if field.count == PLY_FLOAT || field.count == PLY_DOUBLE {
+					return nil, fmt.Errorf("length type %s of field %s is not an integer type", option, f.Name)
+				}
+			case TypeConverter(option) != 0:
+				field.typ = TypeConverter(option)
+			default:
+				return nil, fmt.Errorf("unknown option %s in the tag of field %s", option, f.Name)
+			}
+		}
+
+		element := f.Type
+		if field.list {
+			element = f.Type.Elem()
+		}
+		if plyGoType(element.Kind()) == PLY_START_TYPE {
+			return nil, fmt.Errorf("field %s of type %s can not hold a property", f.Name, f.Type)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// plyGoType returns the PLY type matching a kind of Go number, PLY_START_TYPE if there is none
+func plyGoType(kind reflect.Kind) int {
+	switch kind {
+	case reflect.Int8:
+		return PLY_CHAR
+	case reflect.Uint8:
+		return PLY_UCHAR
+	case reflect.Int16:
+		return PLY_SHORT
+	case reflect.Uint16:
+		return PLY_USHORT
+	case reflect.Int32, reflect.Int, reflect.Int64:
+		return PLY_INT
+	case reflect.Uint32, reflect.Uint, reflect.Uint64:
+		return PLY_UINT
+	case reflect.Float32:
+		return PLY_FLOAT
+	case reflect.Float64:
+		return PLY_DOUBLE
+	}
+	return PLY_START_TYPE
+}
+
+// plySetNumber converts a value to the type of a Go number
+func plySetNumber(value reflect.Value, number float64) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(int64(number))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(int64(number)))
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(number)
+	}
+}
+
+// plyGetNumber returns the value of a Go number
+func plyGetNumber(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	return 0
+}
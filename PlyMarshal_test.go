@@ -0,0 +1,159 @@
+package plyReaderRealsense
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testVertex struct {
+	X, Y, Z    float32
+	Confidence uint8   `ply:"confidence"`
+	Depth      float64 `ply:"depth,double"`
+	Skipped    int     `ply:"-"`
+}
+
+type testFace struct {
+	Indices []int32 `ply:"vertex_indices,list"`
+	Flags   int16   `ply:"flags"`
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	vertices := []testVertex{
+		{X: 0.1, Y: float32(math.Pi), Z: -1e-30, Confidence: 255, Depth: 1.0 / 3},
+		{X: math.MaxFloat32, Y: math.SmallestNonzeroFloat32, Z: 0, Confidence: 0, Depth: -1e300},
+	}
+	faces := []testFace{{Indices: []int32{0, 1, 1}, Flags: -2}, {Indices: []int32{1, 0, 1, 0}}, {Indices: []int32{}}}
+	for _, file_type := range []int{PLY_ASCII, PLY_BINARY_LE, PLY_BINARY_BE} {
+		t.Run(plyFormatName(file_type), func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "marshal.ply")
+			if err := Marshal(filename, file_type, map[string]any{"vertex": vertices, "face": faces}); err != nil {
+				t.Fatal(err)
+			}
+			var read_vertices []testVertex
+			var read_faces []testFace
+			if err := Unmarshal(filename, map[string]any{"vertex": &read_vertices, "face": &read_faces}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(read_vertices, vertices) {
+				t.Errorf("vertices %v, want %v", read_vertices, vertices)
+			}
+			if !reflect.DeepEqual(read_faces, faces) {
+				t.Errorf("faces %v, want %v", read_faces, faces)
+			}
+		})
+	}
+}
+
+func TestMarshalListLength(t *testing.T) {
+	type tagged struct {
+		Indices []uint32 `ply:"vertex_indices,list,uint"`
+	}
+	long := make([]int32, 300)
+	for i := range long {
+		long[i] = int32(i)
+	}
+	tests := []struct {
+		name     string
+		faces    any
+		count    int
+		elements []int32
+	}{
+		{"short lists", []testFace{{Indices: []int32{0, 1, 2}}}, PLY_UCHAR, []int32{0, 1, 2}},
+		{"300 indices", []testFace{{Indices: long}}, PLY_USHORT, long},
+		{"tagged", []tagged{{Indices: []uint32{0, 1, 2}}}, PLY_UINT, []int32{0, 1, 2}},
+	}
+	for _, file_type := range []int{PLY_ASCII, PLY_BINARY_LE} {
+		for _, test := range tests {
+			t.Run(plyFormatName(file_type)+" "+test.name, func(t *testing.T) {
+				filename := filepath.Join(t.TempDir(), "marshal.ply")
+				if err := Marshal(filename, file_type, map[string]any{"face": test.faces}); err != nil {
+					t.Fatal(err)
+				}
+				plyfile, err := OpenPLY(filename)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer PlyClose(plyfile)
+				props, _, _ := PlyGetElementDescription(plyfile, "face")
+				if props[0].Count_external != test.count {
+					t.Errorf("count type %s, want %s", TypeConverterInverse(props[0].Count_external), TypeConverterInverse(test.count))
+				}
+				PlyClose(plyfile)
+
+				var read_faces []testFace
+				if err := Unmarshal(filename, map[string]any{"face": &read_faces}); err != nil {
+					t.Fatal(err)
+				}
+				if len(read_faces) != 1 || !reflect.DeepEqual(read_faces[0].Indices, test.elements) {
+					t.Errorf("faces %v, want %v", read_faces, test.elements)
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalOutOfRange(t *testing.T) {
+	type wide struct {
+		Index int64 `ply:"index"`
+	}
+	type narrow struct {
+		Indices []int32 `ply:"vertex_indices,list,uchar"`
+	}
+	type floatCount struct {
+		Indices []int32 `ply:"vertex_indices,list,float"`
+	}
+	tests := []struct {
+		name     string
+		elements any
+		want     string
+	}{
+		{"int64 over int", []wide{{Index: 1 << 40}}, "value 1.099511627776e+12 of property index out of the range of int"},
+		{"list over uchar", []narrow{{Indices: make([]int32, 256)}}, "list vertex_indices of 256 values too long for its uchar length"},
+		{"float length", []floatCount{{}}, "length type float of field Indices is not an integer type"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "marshal.ply")
+			err := Marshal(filename, PLY_BINARY_LE, map[string]any{"face": test.elements})
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("err = %v, want %q", err, test.want)
+			}
+			if _, err := os.Stat(filename); !os.IsNotExist(err) {
+				t.Errorf("file written with values out of range")
+			}
+		})
+	}
+}
+
+func TestUnmarshalSkipsElements(t *testing.T) {
+	// the faces are cut short, which is seen only if they are decoded
+	filename := filepath.Join(t.TempDir(), "marshal.ply")
+	vertices := []testVertex{{X: 1, Y: 2, Z: 3}, {X: 4, Y: 5, Z: 6}}
+	faces := []testFace{{Indices: []int32{0, 1, 1}}, {Indices: []int32{1, 0, 1}}}
+	if err := Marshal(filename, PLY_BINARY_LE, map[string]any{"vertex": vertices, "face": faces}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Truncate(filename, info.Size()-4); err != nil {
+		t.Fatal(err)
+	}
+
+	var read_vertices []testVertex
+	if err = Unmarshal(filename, map[string]any{"vertex": &read_vertices}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read_vertices, vertices) {
+		t.Errorf("vertices %v, want %v", read_vertices, vertices)
+	}
+	var read_faces []testFace
+	if err = Unmarshal(filename, map[string]any{"face": &read_faces}); err == nil {
+		t.Error("no error for truncated faces")
+	}
+}
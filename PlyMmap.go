@@ -0,0 +1,46 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package plyReaderRealsense
+
+import (
+	"os"
+	"syscall"
+)
+
+/* PlyOpenMapped maps a binary little endian PLY file in memory and returns views on its vertex and face blocks without copying nor decoding them. The vertices must be made of the 3 float properties x, y and z, and the faces of a uchar count and 3 int indices, as in the files exported by the RealSense Viewer. Where the architecture does not allow unaligned reads and the vertex block is not aligned after the header, the vertices are copied. The file is mapped copy on write : the slices can be modified, by AddNoise32 for example, without changing the file. */
+func PlyOpenMapped(filename string) (*PlyMapped, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return nil, ErrNotPLY
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := &PlyMapped{data: data}
+	if err = mapped.locate(); err != nil {
+		mapped.Close()
+		return nil, err
+	}
+	return mapped, nil
+}
+
+/* Close unmaps the file, the slices of the PlyMapped are no longer valid after it */
+func (m *PlyMapped) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(m.data)
+	m.data, m.Vertices, m.Faces = nil, nil, nil
+	return err
+}
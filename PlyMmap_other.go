@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package plyReaderRealsense
+
+import "errors"
+
+/* PlyOpenMapped is not supported on this system, OpenPLY and the iterators read the same files */
+func PlyOpenMapped(filename string) (*PlyMapped, error) {
+	return nil, errors.New("memory mapped PLY files are not supported on this system")
+}
+
+/* Close does nothing on this system */
+func (m *PlyMapped) Close() error {
+	return nil
+}
@@ -0,0 +1,78 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+const mappedHeader = "ply\nformat binary_little_endian 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\n"
+
+func TestMappedVerticesWritable(t *testing.T) {
+	data := leBytes(float32(1), float32(2), float32(3), float32(4), float32(5), float32(6), uint8(3), int32(0), int32(1), int32(0))
+	filename := writeTestFile(t, "mapped.ply", mappedHeader+"end_header\n", data)
+	before, _ := os.ReadFile(filename)
+
+	m, err := PlyOpenMapped(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Vertices) != 2 || m.Vertices[1] != (VertexMono{4, 5, 6}) {
+		t.Fatalf("vertices = %v", m.Vertices)
+	}
+	if m.Faces.Len() != 1 || m.Faces.At(0) != (Face32{0, 1, 0}) {
+		t.Fatalf("face = %v", m.Faces.At(0))
+	}
+
+	// the mapping is private : writing the views must neither crash nor change the file
+	m.Vertices[0].X = 10
+	if m.Vertices[0].X != 10 {
+		t.Errorf("vertex not modified")
+	}
+	if err = m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(filename)
+	if !bytes.Equal(before, after) {
+		t.Errorf("the file was modified through the mapping")
+	}
+}
+
+func TestMappedMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		data   []byte
+		want   string
+	}{
+		{"negative list", mappedHeader + "element edge 1\nproperty list char int v\nend_header\n",
+			leBytes(make([]float32, 6), uint8(3), make([]int32, 3), int8(-1), int32(0)), "negative length"},
+		{"truncated list", mappedHeader + "element edge 1\nproperty list char int v\nend_header\n",
+			leBytes(make([]float32, 6), uint8(3), make([]int32, 3), int8(100), int32(0)), ErrTruncated.Error()},
+		{"huge vertex count", strings.Replace(mappedHeader, "vertex 2", "vertex 9223372036854775807", 1) + "end_header\n",
+			leBytes(make([]float32, 6)), ErrTruncated.Error()},
+		{"huge face count", strings.Replace(mappedHeader, "face 1", "face 9223372036854775807", 1) + "end_header\n",
+			leBytes(make([]float32, 6)), ErrTruncated.Error()},
+		{"huge fixed count", mappedHeader + "element camera 9223372036854775807\nproperty double a\nproperty double b\nend_header\n",
+			leBytes(make([]float32, 6), uint8(3), make([]int32, 3)), ErrTruncated.Error()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := PlyOpenMapped(writeTestFile(t, "bad.ply", test.header, test.data))
+			if err == nil {
+				m.Close()
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("err = %v, want %s", err, test.want)
+			}
+			if test.want == ErrTruncated.Error() && !errors.Is(err, ErrTruncated) {
+				t.Errorf("err = %v is not ErrTruncated", err)
+			}
+		})
+	}
+}
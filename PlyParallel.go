@@ -0,0 +1,47 @@
+package plyReaderRealsense
+
+import (
+	"runtime"
+	"sync"
+)
+
+// smallest number of elements worth giving to a goroutine
+const PLY_MIN_PER_WORKER = 1024
+
+/* PlySetConcurrency sets the number of goroutines decoding the binary data of the file in parallel : 0, the default, uses GOMAXPROCS goroutines and 1 decodes everything in the calling goroutine. The ascii data are always decoded sequentially. */
+func PlySetConcurrency(plyfile *PlyFile, workers int) {
+	if workers < 0 {
+		workers = 0
+	}
+	plyfile.workers = workers
+}
+
+// plyWorkers returns the number of goroutines decoding the data of the file
+func plyWorkers(plyfile *PlyFile) int {
+	if plyfile.workers > 0 {
+		return plyfile.workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// plyParallel splits the elements [0, count) into contiguous ranges, one for each worker, and calls decode on every range in its own goroutine
+func plyParallel(count int, workers int, decode func(lo, hi int)) {
+	if max := (count + PLY_MIN_PER_WORKER - 1) / PLY_MIN_PER_WORKER; workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		decode(0, count)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		lo, hi := count*w/workers, count*(w+1)/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decode(lo, hi)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,137 @@
+package plyReaderRealsense
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// size of the buffer the header and the data are written through
+const PLY_WRITE_BUFFER_SIZE = 1 << 16
+
+/* PlyPutElementHuge writes all the elements of a type at once from a slice of structs (or any value of fixed size) laid out as the properties described in the header, as PlyGetElementHuge reads them. The data go through a buffer flushed by PlyClose instead of a system call for each element. When an element is chosen by PlyPutElementSetup, the data must make whole elements of it : their number, counted from the size of their properties and the lengths of their lists, is the count written by a file opened with PlyOpenStreaming, so a flat slice of numbers counts as well as a slice of structs. In ascii mode the numbers of element are cut into the properties of the element chosen by PlyPutElementSetup, one element per line : a slice of structs or a flat slice of numbers are written alike, each value in the type of its property. */
+func PlyPutElementHuge(plyfile *PlyFile, element interface{}) error {
+	switch plyfile.file_type {
+	case PLY_BINARY_LE, PLY_BINARY_BE:
+		order := plyByteOrder(plyfile)
+		size := binary.Size(element)
+		if size < 0 {
+			return fmt.Errorf("%T has no fixed size", element)
+		}
+		elem := plyWrittenElement(plyfile, "")
+		if elem == nil {
+			if err := plyCountWritten(plyfile, 0); err != nil {
+				return err
+			}
+			return binary.Write(plyDataWriter(plyfile), order, element)
+		}
+
+		// the elements are counted from the bytes, the lengths of the lists being read in the encoded data
+		var block []byte
+		if _, fixed := PlyElementStride(elem.props); !fixed {
+			var buffer bytes.Buffer
+			if err := binary.Write(&buffer, order, element); err != nil {
+				return err
+			}
+			block = buffer.Bytes()
+		}
+		n, err := plyCountBinary(elem, size, block, order)
+		if err != nil {
+			return err
+		}
+		if err = plyCountWritten(plyfile, n); err != nil {
+			return err
+		}
+		if block != nil {
+			_, err = plyDataWriter(plyfile).Write(block)
+			return err
+		}
+		return binary.Write(plyDataWriter(plyfile), order, element)
+
+	case PLY_ASCII:
+		numbers, err := plyAppendNumbers(nil, reflect.ValueOf(element))
+		if err != nil {
+			return err
+		}
+		return plyPutAscii(plyfile, plyWrittenElement(plyfile, ""), numbers, false)
+	}
+	return ErrUnsupportedFormat
+}
+
+// plyDataWriter returns the buffered writer the header and the data are written to
+func plyDataWriter(plyfile *PlyFile) *bufio.Writer {
+	if plyfile.buffer == nil {
+		var w io.Writer = plyfile.Fp
+		if plyfile.compressor != nil {
+			w = plyfile.compressor
+		}
+		plyfile.buffer = bufio.NewWriterSize(w, PLY_WRITE_BUFFER_SIZE)
+	}
+	return plyfile.buffer
+}
+
+// plyWrittenElement returns the element the data are written for : the one chosen by PlyPutElementSetup, or else the element named fallback, nil if there is none
+func plyWrittenElement(plyfile *PlyFile, fallback string) *PlyElement {
+	for i := 0; i < len(plyfile.elems); i++ {
+		if plyfile.elems[i].marker == 1 {
+			return &plyfile.elems[i]
+		}
+	}
+	for i := 0; i < len(plyfile.elems); i++ {
+		if fallback != "" && plyfile.elems[i].name == fallback {
+			return &plyfile.elems[i]
+		}
+	}
+	return nil
+}
+
+// plyPutAscii writes the elements held by numbers as lines following the properties of elem and counts them, one telling that numbers must hold exactly one element
+func plyPutAscii(plyfile *PlyFile, elem *PlyElement, numbers []float64, one bool) error {
+	if elem == nil {
+		return errors.New("no element chosen by PlyPutElementSetup for the ascii data")
+	}
+	n, err := plyAsciiElements(elem, numbers)
+	if err != nil {
+		return err
+	}
+	if one && n != 1 {
+		return fmt.Errorf("%d values make %d elements %s instead of one", len(numbers), n, elem.name)
+	}
+	if err = plyCountWritten(plyfile, n); err != nil {
+		return err
+	}
+	return plyWriteAsciiElements(plyDataWriter(plyfile), elem.props, numbers)
+}
+
+// plyAppendNumbers appends the numbers contained in value (a pointer, slice, array or struct of numbers) to numbers, in the order of their fields
+func plyAppendNumbers(numbers []float64, value reflect.Value) ([]float64, error) {
+	var err error
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return plyAppendNumbers(numbers, value.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len() && err == nil; i++ {
+			numbers, err = plyAppendNumbers(numbers, value.Index(i))
+		}
+		return numbers, err
+	case reflect.Struct:
+		for i := 0; i < value.NumField() && err == nil; i++ {
+			numbers, err = plyAppendNumbers(numbers, value.Field(i))
+		}
+		return numbers, err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return append(numbers, float64(value.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return append(numbers, float64(value.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return append(numbers, value.Float()), nil
+	}
+	return numbers, errors.New("cannot write ascii values from " + value.Type().String())
+}
@@ -0,0 +1,116 @@
+package plyReaderRealsense
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// openTestWriter opens a file in a temporary directory with a vertex element of the given scalar properties and a face element of vertex indices
+func openTestWriter(t *testing.T, file_type int, num_vertices, num_faces int, vertex_props ...PlyProperty) (*PlyFile, string) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "out.ply")
+	version := float32(1)
+	plyfile, err := PlyOpenForWriting(filename, 2, []string{"vertex", "face"}, file_type, &version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, prop := range vertex_props {
+		PlyDescribeProperty(plyfile, "vertex", prop)
+	}
+	PlyDescribeProperty(plyfile, "face", *New_property("vertex_indices", PLY_INT, PLY_INT, 0, 1, PLY_UCHAR, PLY_UCHAR, 0))
+	PlyElementCount(plyfile, "vertex", num_vertices)
+	PlyElementCount(plyfile, "face", num_faces)
+	if err = PlyHeaderComplete(plyfile); err != nil {
+		t.Fatal(err)
+	}
+	return plyfile, filename
+}
+
+// scalarProps describes scalar properties of the same type
+func scalarProps(typ int, names ...string) []PlyProperty {
+	props := make([]PlyProperty, len(names))
+	for i, name := range names {
+		props[i] = *New_property(name, typ, typ, 0, 0, 0, 0, 0)
+	}
+	return props
+}
+
+// asciiData returns the data written after the header of an ascii file
+func asciiData(t *testing.T, filename string) string {
+	t.Helper()
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.SplitN(string(content), "end_header\n", 2)[1]
+}
+
+func TestPutElementAscii(t *testing.T) {
+	xyz := scalarProps(PLY_FLOAT, "x", "y", "z")
+	colored := append(scalarProps(PLY_FLOAT, "x", "y", "z"), scalarProps(PLY_UCHAR, "red", "green", "blue")...)
+	tests := []struct {
+		name  string
+		props []PlyProperty
+		write func(*PlyFile) error
+		want  string // data written, "" when an error is expected
+	}{
+		{"flat huge", xyz, func(p *PlyFile) error {
+			PlyPutElementSetup(p, "vertex")
+			return PlyPutElementHuge(p, []float32{1, 2, 3, 4, 5, 6.5})
+		}, "1 2 3\n4 5 6.5\n"},
+		{"struct huge", xyz, func(p *PlyFile) error {
+			PlyPutElementSetup(p, "vertex")
+			return PlyPutElementHuge(p, []VertexMono{{X: 1, Y: 2, Z: 3}, {X: 0.1, Y: 5, Z: 6}})
+		}, "1 2 3\n0.1 5 6\n"},
+		{"huge without setup", xyz, func(p *PlyFile) error {
+			return PlyPutElementHuge(p, []float32{1, 2, 3})
+		}, ""},
+		{"huge partial element", xyz, func(p *PlyFile) error {
+			PlyPutElementSetup(p, "vertex")
+			return PlyPutElementHuge(p, []float32{1, 2, 3, 4})
+		}, ""},
+		{"huge faces", xyz, func(p *PlyFile) error {
+			PlyPutElementSetup(p, "face")
+			return PlyPutElementHuge(p, []FaceReadingHuge{{Nverts: 3, Vert1: 0, Vert2: 1, Vert3: 2}})
+		}, "3 0 1 2\n"},
+		{"vertex with color", colored, func(p *PlyFile) error {
+			return PlyPutElement(p, Vertex{X: 1, Y: 2, Z: 3, R: 255, G: 128, B: 0})
+		}, "1 2 3 255 128 0\n"},
+		{"vertex without color", xyz, func(p *PlyFile) error {
+			return PlyPutElement(p, Vertex{X: 1, Y: 2, Z: 3})
+		}, ""},
+		{"declared type", scalarProps(PLY_INT, "x", "y", "z", "nx", "ny", "nz"), func(p *PlyFile) error {
+			return PlyPutElementNormal(p, VertexNormal{X: 1.9, Y: -2, Z: 3, NX: 0, NY: 1, NZ: 0})
+		}, "1 -2 3 0 1 0\n"},
+		{"face", xyz, func(p *PlyFile) error {
+			return PlyPutElementFace(p, FaceReading{Nverts: 3, Vert1: [4]byte{0}, Vert2: [4]byte{1}, Vert3: [4]byte{2}})
+		}, "3 0 1 2\n"},
+		{"face of 4 vertices", xyz, func(p *PlyFile) error {
+			return PlyPutElementFace(p, FaceReading{Nverts: 4, Vert1: [4]byte{0}, Vert2: [4]byte{1}, Vert3: [4]byte{2}})
+		}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plyfile, filename := openTestWriter(t, PLY_ASCII, 2, 1, test.props...)
+			err := test.write(plyfile)
+			if test.want == "" {
+				PlyAbort(plyfile)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = PlyClose(plyfile); err != nil {
+				t.Fatal(err)
+			}
+			if data := asciiData(t, filename); data != test.want {
+				t.Errorf("data = %q, want %q", data, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package plyReaderRealsense
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Go types the values of a property can be converted to
+type PlyNumber interface {
+	~int8 | ~uint8 | ~int16 | ~uint16 | ~int32 | ~uint32 | ~int64 | ~uint64 | ~int | ~uint | ~float32 | ~float64
+}
+
+/* PlyPropertyValues converts the scalar values of a decoded property to the Go type chosen by the caller, whatever their type in the file. The values are converted as by T(value) : the fractional part is dropped for an integer T, and a value out of the range of T, or negative for an unsigned T, gives a result which depends on the machine. Choose a T holding the type of the property in the file (PlyTypeSize, the External_type of Prop) to keep every value. */
+func PlyPropertyValues[T PlyNumber](prop *PlyPropertyData) []T {
+	values := make([]T, len(prop.Values))
+	for i, value := range prop.Values {
+		values[i] = T(value)
+	}
+	return values
+}
+
+/* PlyPropertyLists converts the list values of a decoded property to the Go type chosen by the caller, as PlyPropertyValues does for the scalar values */
+func PlyPropertyLists[T PlyNumber](prop *PlyPropertyData) [][]T {
+	lists := make([][]T, len(prop.Lists))
+	for i, list := range prop.Lists {
+		lists[i] = make([]T, len(list))
+		for k, value := range list {
+			lists[i][k] = T(value)
+		}
+	}
+	return lists
+}
+
+/* PlyPutElementProperties writes all the elements of a type from the columns of their properties. The columns must hold the properties declared for the element chosen by PlyPutElementSetup, or else for the first element declared with properties of these names, in the same order and with the same types, and must all have the same length. Each value is converted to the type its property has in the header, an error being returned for an integer value out of its range or a list longer than its length type can tell. In ascii mode each element is written on its own line, the lists preceded by their length and the floats with the shortest representation giving back the same value. */
+func PlyPutElementProperties(plyfile *PlyFile, data []PlyPropertyData) error {
+	elem, err := plyColumnsElement(plyfile, data)
+	if err != nil {
+		return err
+	}
+	props := elem.props
+	num_elems := PlyColumnsLength(data)
+	if err = plyCountWritten(plyfile, num_elems); err != nil {
+		return err
+	}
+	if plyfile.file_type == PLY_ASCII {
+		return plyPutElementPropertiesAscii(plyDataWriter(plyfile), props, data)
+	}
+	if plyfile.file_type != PLY_BINARY_LE && plyfile.file_type != PLY_BINARY_BE {
+		return ErrUnsupportedFormat
+	}
+	order := plyByteOrder(plyfile)
+
+	var block []byte
+	for i := 0; i < num_elems; i++ {
+		for j := 0; j < len(props); j++ {
+			prop := props[j]
+			if PlyTypeSize(plyFileType(prop)) == 0 || (prop.Is_list == 1 && PlyTypeSize(plyFileCountType(prop)) == 0) {
+				return errors.New("unknown type for property " + prop.Name)
+			}
+			if prop.Is_list == 0 {
+				block = plyAppendScalar(block, plyFileType(prop), data[j].Values[i], order)
+				continue
+			}
+			list := data[j].Lists[i]
+			block = plyAppendScalar(block, plyFileCountType(prop), float64(len(list)), order)
+			for _, value := range list {
+				block = plyAppendScalar(block, plyFileType(prop), value, order)
+			}
+		}
+	}
+
+	_, err = plyDataWriter(plyfile).Write(block)
+	return err
+}
+
+// plyColumnsElement returns the element the columns are written for, after checking that they hold its declared properties and all have the same length
+func plyColumnsElement(plyfile *PlyFile, data []PlyPropertyData) (*PlyElement, error) {
+	elem := plyWrittenElement(plyfile, "")
+	for i := 0; i < len(plyfile.elems) && elem == nil; i++ {
+		if plySameNames(plyfile.elems[i].props, data) {
+			elem = &plyfile.elems[i]
+		}
+	}
+	if elem == nil {
+		return nil, errors.New("no element declared with the properties of the columns")
+	}
+	if len(elem.props) != len(data) {
+		return nil, fmt.Errorf("%d columns for the %d properties of element %s", len(data), len(elem.props), elem.name)
+	}
+
+	num_elems := PlyColumnsLength(data)
+	for j := 0; j < len(data); j++ {
+		prop, declared := data[j].Prop, elem.props[j]
+		if prop.Name != declared.Name || prop.Is_list != declared.Is_list || plyFileType(prop) != plyFileType(declared) || (prop.Is_list == 1 && plyFileCountType(prop) != plyFileCountType(declared)) {
+			return nil, fmt.Errorf("column %s does not match property %s of element %s", prop.Name, declared.Name, elem.name)
+		}
+		if length := PlyColumnsLength(data[j : j+1]); length != num_elems {
+			return nil, fmt.Errorf("element %s: property %s has %d values instead of %d", elem.name, prop.Name, length, num_elems)
+		}
+
+		// the values must fit their types, the data would be corrupt otherwise
+		if prop.Is_list == 0 {
+			for _, value := range data[j].Values {
+				if err := plyCheckValue(plyFileType(declared), value, declared.Name); err != nil {
+					return nil, fmt.Errorf("element %s: %w", elem.name, err)
+				}
+			}
+			continue
+		}
+		for _, list := range data[j].Lists {
+			if err := plyCheckList(declared, list); err != nil {
+				return nil, fmt.Errorf("element %s: %w", elem.name, err)
+			}
+		}
+	}
+	return elem, nil
+}
+
+/* PlyTypeRange returns the smallest and the largest values of an integer type of a PLY file, integer being false for the float types, which are not bounded */
+func PlyTypeRange(typ int) (min float64, max float64, integer bool) {
+	switch typ {
+	case PLY_CHAR:
+		return math.MinInt8, math.MaxInt8, true
+	case PLY_UCHAR:
+		return 0, math.MaxUint8, true
+	case PLY_SHORT:
+		return math.MinInt16, math.MaxInt16, true
+	case PLY_USHORT:
+		return 0, math.MaxUint16, true
+	case PLY_INT:
+		return math.MinInt32, math.MaxInt32, true
+	case PLY_UINT:
+		return 0, math.MaxUint32, true
+	}
+	return 0, 0, false
+}
+
+// plyCheckValue returns an error if value is out of the range of the integer type typ of the property name, the fractional part being dropped when it is written
+func plyCheckValue(typ int, value float64, name string) error {
+	min, max, integer := PlyTypeRange(typ)
+	if integer && !(value >= min && value < max+1) {
+		return fmt.Errorf("value %g of property %s out of the range of %s", value, name, TypeConverterInverse(typ))
+	}
+	return nil
+}
+
+// plyCheckList returns an error if the length of a list does not fit its count type or one of its values does not fit the type of the property
+func plyCheckList(prop PlyProperty, list []float64) error {
+	if _, max, _ := PlyTypeRange(plyFileCountType(prop)); float64(len(list)) > max {
+		return fmt.Errorf("list %s of %d values too long for its %s length", prop.Name, len(list), TypeConverterInverse(plyFileCountType(prop)))
+	}
+	for _, value := range list {
+		if err := plyCheckValue(plyFileType(prop), value, prop.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plySameNames tells whether the columns hold properties of the same names as props, in the same order
+func plySameNames(props []PlyProperty, data []PlyPropertyData) bool {
+	if len(props) != len(data) {
+		return false
+	}
+	for j := range props {
+		if props[j].Name != data[j].Prop.Name {
+			return false
+		}
+	}
+	return true
+}
+
+/* PlyColumnsLength returns the number of elements held by the columns of their properties */
+func PlyColumnsLength(data []PlyPropertyData) int {
+	if len(data) == 0 {
+		return 0
+	}
+	if data[0].Prop.Is_list == 1 {
+		return len(data[0].Lists)
+	}
+	return len(data[0].Values)
+}
+
+// plyAppendScalar encodes a value in the binary form of the given type at the end of b, the values being checked against the range of the type by plyColumnsElement or plyAsciiElements
+func plyAppendScalar(b []byte, typ int, value float64, order binary.AppendByteOrder) []byte {
+	switch typ {
+	case PLY_CHAR:
+		return append(b, byte(int64(value)))
+	case PLY_UCHAR:
+		return append(b, byte(int64(value)))
+	case PLY_SHORT:
+		return order.AppendUint16(b, uint16(int64(value)))
+	case PLY_USHORT:
+		return order.AppendUint16(b, uint16(int64(value)))
+	case PLY_INT:
+		return order.AppendUint32(b, uint32(int64(value)))
+	case PLY_UINT:
+		return order.AppendUint32(b, uint32(int64(value)))
+	case PLY_FLOAT:
+		return order.AppendUint32(b, math.Float32bits(float32(value)))
+	case PLY_DOUBLE:
+		return order.AppendUint64(b, math.Float64bits(value))
+	}
+	return b
+}
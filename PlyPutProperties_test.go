@@ -0,0 +1,134 @@
+package plyReaderRealsense
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// columns builds scalar columns of the given type and values
+func columns(typ int, names []string, values ...[]float64) []PlyPropertyData {
+	data := make([]PlyPropertyData, len(names))
+	for j, name := range names {
+		data[j].Prop = *New_property(name, typ, typ, 0, 0, 0, 0, 0)
+		data[j].Values = values[j]
+	}
+	return data
+}
+
+func TestPutElementPropertiesChecks(t *testing.T) {
+	xy := []string{"x", "y"}
+	tests := []struct {
+		name string
+		data []PlyPropertyData
+		want string // part of the error, "" if none is expected
+	}{
+		{"valid", columns(PLY_FLOAT, xy, []float64{1, 2}, []float64{3, 4}), ""},
+		{"different lengths", columns(PLY_FLOAT, xy, []float64{1, 2}, []float64{1}), "has 1 values instead of 2"},
+		{"longer column", columns(PLY_FLOAT, xy, []float64{1}, []float64{1, 2}), "has 2 values instead of 1"},
+		{"other type", columns(PLY_DOUBLE, xy, []float64{1, 2}, []float64{3, 4}), "does not match"},
+		{"other order", columns(PLY_FLOAT, []string{"y", "x"}, []float64{1, 2}, []float64{3, 4}), "does not match"},
+		{"missing property", columns(PLY_FLOAT, []string{"x"}, []float64{1, 2}), "1 columns for the 2 properties"},
+	}
+	for _, file_type := range []int{PLY_ASCII, PLY_BINARY_LE} {
+		for _, test := range tests {
+			t.Run(plyFormatName(file_type)+" "+test.name, func(t *testing.T) {
+				plyfile, _ := openTestWriter(t, file_type, 2, 0, scalarProps(PLY_FLOAT, "x", "y")...)
+				defer PlyAbort(plyfile)
+				PlyPutElementSetup(plyfile, "vertex")
+				err := PlyPutElementProperties(plyfile, test.data)
+				if test.want == "" {
+					if err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				if err == nil || !strings.Contains(err.Error(), test.want) {
+					t.Errorf("err = %v, want %q", err, test.want)
+				}
+			})
+		}
+	}
+}
+
+func TestPutElementPropertiesFindsElement(t *testing.T) {
+	// without PlyPutElementSetup the element is found by the names of the columns
+	plyfile, filename := openTestWriter(t, PLY_ASCII, 1, 1, scalarProps(PLY_UCHAR, "red")...)
+	if err := PlyPutElementProperties(plyfile, columns(PLY_UCHAR, []string{"red"}, []float64{200})); err != nil {
+		t.Fatal(err)
+	}
+	faces := []PlyPropertyData{{Prop: *New_property("vertex_indices", PLY_INT, PLY_INT, 0, 1, PLY_UCHAR, PLY_UCHAR, 0), Lists: [][]float64{{0, 0, 0}}}}
+	if err := PlyPutElementProperties(plyfile, faces); err != nil {
+		t.Fatal(err)
+	}
+	if err := PlyPutElementProperties(plyfile, columns(PLY_UCHAR, []string{"green"}, []float64{1})); err == nil {
+		t.Error("no error for columns of no element")
+	}
+	if err := PlyClose(plyfile); err != nil {
+		t.Fatal(err)
+	}
+	if data := asciiData(t, filename); data != "200\n3 0 0 0\n" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestPutElementPropertiesRange(t *testing.T) {
+	long := make([]float64, 256)
+	tests := []struct {
+		name  string
+		typ   int
+		value float64
+		lists [][]float64 // faces written instead of the vertex when not nil
+		want  string      // part of the error, "" if none is expected
+	}{
+		{"uchar max", PLY_UCHAR, 255, nil, ""},
+		{"uchar over", PLY_UCHAR, 256, nil, "value 256 of property x out of the range of uchar"},
+		{"uchar negative", PLY_UCHAR, -1, nil, "out of the range of uchar"},
+		{"char min", PLY_CHAR, -128, nil, ""},
+		{"char under", PLY_CHAR, -129, nil, "out of the range of char"},
+		{"short fraction", PLY_SHORT, 32767.5, nil, ""},
+		{"int over", PLY_INT, 1 << 31, nil, "out of the range of int"},
+		{"uint max", PLY_UINT, 1<<32 - 1, nil, ""},
+		{"uint NaN", PLY_UINT, math.NaN(), nil, "out of the range of uint"},
+		{"float large", PLY_FLOAT, 1 << 40, nil, ""},
+		{"list of 255", PLY_FLOAT, 0, [][]float64{long[:255]}, ""},
+		{"list of 256", PLY_FLOAT, 0, [][]float64{long}, "list vertex_indices of 256 values too long for its uchar length"},
+		{"index over", PLY_FLOAT, 0, [][]float64{{0, 1, 1 << 31}}, "out of the range of int"},
+	}
+	for _, file_type := range []int{PLY_ASCII, PLY_BINARY_LE} {
+		for _, test := range tests {
+			t.Run(plyFormatName(file_type)+" "+test.name, func(t *testing.T) {
+				plyfile, _ := openTestWriter(t, file_type, 1, 1, scalarProps(test.typ, "x")...)
+				defer PlyAbort(plyfile)
+				var err error
+				if test.lists == nil {
+					PlyPutElementSetup(plyfile, "vertex")
+					err = PlyPutElementProperties(plyfile, columns(test.typ, []string{"x"}, []float64{test.value}))
+				} else {
+					PlyPutElementSetup(plyfile, "face")
+					faces := []PlyPropertyData{{Prop: *New_property("vertex_indices", PLY_INT, PLY_INT, 0, 1, PLY_UCHAR, PLY_UCHAR, 0), Lists: test.lists}}
+					err = PlyPutElementProperties(plyfile, faces)
+				}
+				if test.want == "" {
+					if err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				if err == nil || !strings.Contains(err.Error(), test.want) {
+					t.Errorf("err = %v, want %q", err, test.want)
+				}
+			})
+		}
+	}
+}
+
+func TestPutElementHugeAsciiRange(t *testing.T) {
+	// the flat numbers written in ascii are checked as the columns are
+	plyfile, _ := openTestWriter(t, PLY_ASCII, 1, 1, scalarProps(PLY_UCHAR, "x")...)
+	defer PlyAbort(plyfile)
+	PlyPutElementSetup(plyfile, "vertex")
+	if err := PlyPutElementHuge(plyfile, []float64{300}); err == nil || !strings.Contains(err.Error(), "out of the range of uchar") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package plyReaderRealsense
+
+import (
+	"errors"
+	"fmt"
+)
+
+/* ReadVertexAt reads the vertex of index i, with its color if the vertices have one, directly at its offset in the file without decoding the data before it. It needs binary data and a file opened by OpenPLY or PlyOpenReaderAt, and does not move the reading position of the iterators. */
+func (plyfile *PlyFile) ReadVertexAt(i int) (Vertex64, error) {
+	vertices, err := plyfile.ReadVertexRange(i, 1)
+	if err != nil {
+		return Vertex64{}, err
+	}
+	return vertices[0], nil
+}
+
+/* ReadVertexRange reads the n vertices starting at index start, with their color if the vertices have one, directly at their offset in the file as ReadVertexAt does. */
+func (plyfile *PlyFile) ReadVertexRange(start, n int) ([]Vertex64, error) {
+	data, err := plyReadElementRange(plyfile, "vertex", start, n)
+	if err != nil {
+		return nil, err
+	}
+
+	it := &VertexIterator{}
+	it.data = data
+	if it.bind(); it.err != nil {
+		return nil, it.err
+	}
+	vertices := make([]Vertex64, n)
+	for it.i = 0; it.i < n; it.i++ {
+		vertices[it.i] = it.VertexColor64()
+	}
+	return vertices, nil
+}
+
+// plyReadElementRange decodes the n elements of a type starting at index start, reading them at their offset computed from the sizes of the elements placed before them
+func plyReadElementRange(plyfile *PlyFile, element_name string, start, n int) ([]PlyPropertyData, error) {
+	if plyfile.file_type == PLY_ASCII {
+		return nil, fmt.Errorf("%w: the elements of ascii data can not be located", ErrUnsupportedFormat)
+	}
+	source := plyRandomSource(plyfile)
+	if source == nil {
+		return nil, errors.New("the elements of a stream can not be read at random")
+	}
+	index := plyElementIndex(plyfile, element_name)
+	if index < 0 {
+		return nil, fmt.Errorf("no element %s in the file", element_name)
+	}
+	elem := plyfile.elems[index]
+	if start < 0 || n < 0 || start+n > elem.num {
+		return nil, fmt.Errorf("elements %d to %d out of the %d elements %s", start, start+n, elem.num, element_name)
+	}
+	stride, fixed := PlyElementStride(elem.props)
+	if !fixed {
+		return nil, fmt.Errorf("element %s has a list property, its elements can not be located", element_name)
+	}
+
+	// the offset of the range follows from the sizes of the elements before it
+	offset, _, err := plyElementSpan(plyfile, index)
+	if err != nil {
+		return nil, err
+	}
+	offset += int64(stride) * int64(start)
+
+	block := make([]byte, stride*n)
+	if _, err := source.ReadAt(block, offset); err != nil {
+		return nil, plyDataError(err, element_name)
+	}
+	data := plyNewColumns(elem.props, n)
+	plyDecodeBlock(plyfile, elem.props, data, block, stride, n)
+	return data, nil
+}
@@ -0,0 +1,144 @@
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// number of vertices of randomHeader, more than one chunk of the iterators
+const randomVertices = PLY_CHUNK_SIZE + 100
+
+// materials with lists of varying lengths placed before colored vertices, then one face
+var randomHeader = "ply\nformat binary_little_endian 1.0\nelement material 3\nproperty list uchar float params\nproperty uchar id\n" +
+	"element vertex " + strconv.Itoa(randomVertices) + "\nproperty float x\nproperty float y\nproperty float z\nproperty uchar red\nproperty uchar green\nproperty uchar blue\n" +
+	"element face 1\nproperty list uchar int vertex_indices\nend_header\n"
+
+// randomVertex is the vertex of index i written by randomData
+func randomVertex(i int) Vertex64 {
+	return Vertex64{X: float64(i), Y: float64(2 * i), Z: -float64(i), R: uint8(i), G: uint8(i / 256), B: 7}
+}
+
+// randomData returns the binary data of randomHeader
+func randomData() []byte {
+	data := leBytes(uint8(2), float32(1), float32(2), uint8(0), uint8(0), uint8(1), uint8(5), float32(1), float32(2), float32(3), float32(4), float32(5), uint8(2))
+	for i := 0; i < randomVertices; i++ {
+		v := randomVertex(i)
+		data = append(data, leBytes(float32(v.X), float32(v.Y), float32(v.Z), v.R, v.G, v.B)...)
+	}
+	return append(data, leBytes(uint8(3), int32(0), int32(1), int32(2))...)
+}
+
+func TestReadVertexAtAfterLists(t *testing.T) {
+	filename := writeTestFile(t, "random.ply", randomHeader, randomData())
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+
+	for _, i := range []int{0, 1, PLY_CHUNK_SIZE, randomVertices - 1} {
+		v, err := plyfile.ReadVertexAt(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != randomVertex(i) {
+			t.Errorf("vertex %d = %v, want %v", i, v, randomVertex(i))
+		}
+	}
+	vertices, err := plyfile.ReadVertexRange(PLY_CHUNK_SIZE-2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range vertices {
+		if v != randomVertex(PLY_CHUNK_SIZE-2+k) {
+			t.Errorf("range vertex %d = %v", k, v)
+		}
+	}
+	if vertices, err = plyfile.ReadVertexRange(randomVertices, 0); err != nil || len(vertices) != 0 {
+		t.Errorf("empty range at the end = %v, %v", vertices, err)
+	}
+}
+
+func TestReadVertexRangeOutOfRange(t *testing.T) {
+	filename := writeTestFile(t, "random.ply", randomHeader, randomData())
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+
+	for _, r := range [][2]int{{-1, 1}, {0, -1}, {randomVertices, 1}, {randomVertices - 1, 2}, {0, randomVertices + 1}} {
+		if _, err := plyfile.ReadVertexRange(r[0], r[1]); err == nil || !strings.Contains(err.Error(), "out of the") {
+			t.Errorf("start %d n %d: err = %v", r[0], r[1], err)
+		}
+	}
+	if _, err := plyfile.ReadVertexAt(randomVertices); err == nil {
+		t.Error("no error for the vertex after the last one")
+	}
+}
+
+func TestReadVertexAtRefused(t *testing.T) {
+	ascii := "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nproperty float z\nend_header\n1 2 3\n"
+	plyfile, err := OpenPLY(writeTestFile(t, "ascii.ply", ascii, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+	if _, err := plyfile.ReadVertexAt(0); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ascii: err = %v, want %v", err, ErrUnsupportedFormat)
+	}
+
+	content := append([]byte(randomHeader), randomData()...)
+	stream, err := PlyOpenReader(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(stream)
+	compressed, err := OpenPLY(writeTestFile(t, "random.ply.gz", string(gzipped(string(content))), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(compressed)
+	for name, plyfile := range map[string]*PlyFile{"stream": stream, "compressed": compressed} {
+		if _, err := plyfile.ReadVertexAt(0); err == nil || !strings.Contains(err.Error(), "stream") {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
+
+func TestReadVertexAtKeepsIterators(t *testing.T) {
+	filename := writeTestFile(t, "random.ply", randomHeader, randomData())
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+	plyfile.workers = 1 // chunks of PLY_CHUNK_SIZE vertices
+
+	// a random read before the iteration, then one between every two vertices, across the chunks
+	if _, err := plyfile.ReadVertexAt(randomVertices - 1); err != nil {
+		t.Fatal(err)
+	}
+	it := plyfile.Vertices()
+	i := 0
+	for ; it.Next(); i++ {
+		if v := it.VertexColor64(); v != randomVertex(i) {
+			t.Fatalf("vertex %d = %v, want %v", i, v, randomVertex(i))
+		}
+		if _, err := plyfile.ReadVertexAt((i * 7919) % randomVertices); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if it.Err() != nil || i != randomVertices {
+		t.Fatalf("%d vertices iterated, err %v", i, it.Err())
+	}
+
+	// the faces follow the vertices
+	faces := plyfile.Faces()
+	if !faces.Next() || faces.Face() != (Face32{X: 0, Y: 1, Z: 2}) || faces.Next() || faces.Err() != nil {
+		t.Errorf("face %v, err %v", faces.Face(), faces.Err())
+	}
+}
@@ -0,0 +1,85 @@
+package plyReaderRealsense
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// number of characters reserved in the header for each count of a streamed file
+const PLY_COUNT_WIDTH = 20
+
+/* PlyOpenStreaming opens a file for writing like PlyOpenForWriting, for elements whose number is not known before they are written (a live capture, the output of a filter...). The properties are described and PlyHeaderComplete is called as usual, without PlyElementCount : the header reserves PLY_COUNT_WIDTH characters for each count. Every element written is then counted for the element chosen by PlyPutElementSetup, and PlyClose writes the final counts in the header. Files compressed with gzip can not be patched and are refused. */
+func PlyOpenStreaming(filename string, nelems int, elem_names []string, file_type int, version *float32) (*PlyFile, error) {
+	plyfile, err := PlyOpenForWriting(filename, nelems, elem_names, file_type, version)
+	if err != nil {
+		return nil, err
+	}
+	if plyfile.compressor != nil {
+		PlyAbort(plyfile)
+		return nil, errors.New("the counts of a compressed file can not be written after its data")
+	}
+	plyfile.counts_at = make([]int64, len(plyfile.elems))
+	return plyfile, nil
+}
+
+// plyCountWritten adds n to the count of the element chosen by PlyPutElementSetup when the counts are written on close
+func plyCountWritten(plyfile *PlyFile, n int) error {
+	if plyfile.counts_at == nil {
+		return nil
+	}
+	elem := plyWrittenElement(plyfile, "")
+	if elem == nil {
+		return errors.New("no element chosen by PlyPutElementSetup for the data written")
+	}
+	elem.num += n
+	return nil
+}
+
+// plyCountBinary returns the number of elements held by size bytes of binary data laid out as the properties of elem : size divided by the stride of the element, or, when the element has lists, the records of block walked one by one
+func plyCountBinary(elem *PlyElement, size int, block []byte, order binary.ByteOrder) (int, error) {
+	stride, fixed := PlyElementStride(elem.props)
+	if fixed {
+		if size == 0 {
+			return 0, nil
+		}
+		if stride == 0 || size%stride != 0 {
+			return 0, fmt.Errorf("%d bytes do not make whole elements %s of %d bytes", size, elem.name, stride)
+		}
+		return size / stride, nil
+	}
+
+	n := 0
+	for offset := 0; offset < len(block); n++ {
+		for _, prop := range elem.props {
+			typ := plyFileType(prop)
+			if prop.Is_list == 1 {
+				if offset+PlyTypeSize(plyFileCountType(prop)) > len(block) {
+					return 0, fmt.Errorf("%d bytes do not make whole elements %s", len(block), elem.name)
+				}
+				count := int(plyDecodeScalar(block[offset:], plyFileCountType(prop), order))
+				if count < 0 {
+					return 0, fmt.Errorf("negative length %d for list %s", count, prop.Name)
+				}
+				offset += PlyTypeSize(plyFileCountType(prop)) + count*PlyTypeSize(typ)
+			} else {
+				offset += PlyTypeSize(typ)
+			}
+		}
+		if offset > len(block) {
+			return 0, fmt.Errorf("%d bytes do not make whole elements %s", len(block), elem.name)
+		}
+	}
+	return n, nil
+}
+
+// plyPatchCounts writes the final count of each element at the place reserved in the header
+func plyPatchCounts(plyfile *PlyFile) error {
+	for i := 0; i < len(plyfile.elems); i++ {
+		count := fmt.Sprintf("%-*d", PLY_COUNT_WIDTH, plyfile.elems[i].num)
+		if _, err := plyfile.Fp.WriteAt([]byte(count), plyfile.counts_at[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
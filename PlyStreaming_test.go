@@ -0,0 +1,121 @@
+package plyReaderRealsense
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamingCounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		file_type int
+		write     func(*PlyFile) error
+		vertices  int
+		faces     int
+	}{
+		{"flat binary", PLY_BINARY_LE, func(p *PlyFile) error {
+			return PlyPutElementHuge(p, []float32{1, 2, 3, 4, 5, 6})
+		}, 2, 0},
+		{"structs big endian", PLY_BINARY_BE, func(p *PlyFile) error {
+			return PlyPutElementHuge(p, []VertexMono{{X: 1}, {X: 2}, {X: 3}})
+		}, 3, 0},
+		{"flat ascii", PLY_ASCII, func(p *PlyFile) error {
+			return PlyPutElementHuge(p, []float32{1, 2, 3, 4, 5, 6})
+		}, 2, 0},
+		{"several calls", PLY_BINARY_LE, func(p *PlyFile) error {
+			if err := PlyPutElementHuge(p, []float32{1, 2, 3}); err != nil {
+				return err
+			}
+			if err := PlyPutElementHuge(p, VertexMono{X: 4}); err != nil {
+				return err
+			}
+			return PlyPutElementHuge(p, []VertexMono{{X: 5}, {X: 6}})
+		}, 4, 0},
+		{"faces", PLY_BINARY_LE, func(p *PlyFile) error {
+			if err := PlyPutElementHuge(p, []float32{1, 2, 3}); err != nil {
+				return err
+			}
+			PlyPutElementSetup(p, "face")
+			return PlyPutElementHuge(p, []FaceReadingHuge{{Nverts: 3}, {Nverts: 3}})
+		}, 1, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plyfile, filename := openTestStreaming(t, test.file_type)
+			if err := test.write(plyfile); err != nil {
+				t.Fatal(err)
+			}
+			if err := PlyClose(plyfile); err != nil {
+				t.Fatal(err)
+			}
+
+			header, err := PlyInspect(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header.Elements[0].Count != test.vertices || header.Elements[1].Count != test.faces {
+				t.Errorf("counts = %d, %d, want %d, %d", header.Elements[0].Count, header.Elements[1].Count, test.vertices, test.faces)
+			}
+			if test.file_type != PLY_ASCII && !header.SizeMatches {
+				t.Errorf("the size of the file does not match its header")
+			}
+		})
+	}
+}
+
+func TestStreamingPartialElements(t *testing.T) {
+	tests := []struct {
+		name    string
+		element string
+		data    interface{}
+	}{
+		{"partial vertex", "vertex", []float32{1, 2, 3, 4}},
+		{"partial face", "face", []uint8{3, 0, 0, 0, 0}},
+		{"face list too long", "face", []FaceReadingHuge{{Nverts: 4}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plyfile, _ := openTestStreaming(t, PLY_BINARY_LE)
+			defer PlyAbort(plyfile)
+			PlyPutElementSetup(plyfile, test.element)
+			if err := PlyPutElementHuge(plyfile, test.data); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
+
+func TestStreamingWithoutSetup(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "live.ply")
+	version := float32(1)
+	plyfile, err := PlyOpenStreaming(filename, 1, []string{"vertex"}, PLY_BINARY_LE, &version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyAbort(plyfile)
+	PlyDescribeProperty(plyfile, "vertex", *New_property("x", PLY_FLOAT, PLY_FLOAT, 0, 0, 0, 0, 0))
+	PlyHeaderComplete(plyfile)
+	if err = PlyPutElementHuge(plyfile, []float32{1}); err == nil {
+		t.Error("no error for data written without PlyPutElementSetup")
+	}
+}
+
+// openTestStreaming opens a streamed file of float x, y, z vertices and faces of vertex indices, the vertex element being chosen
+func openTestStreaming(t *testing.T, file_type int) (*PlyFile, string) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "live.ply")
+	version := float32(1)
+	plyfile, err := PlyOpenStreaming(filename, 2, []string{"vertex", "face"}, file_type, &version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, prop := range scalarProps(PLY_FLOAT, "x", "y", "z") {
+		PlyDescribeProperty(plyfile, "vertex", prop)
+	}
+	PlyDescribeProperty(plyfile, "face", *New_property("vertex_indices", PLY_INT, PLY_INT, 0, 1, PLY_UCHAR, PLY_UCHAR, 0))
+	if err = PlyHeaderComplete(plyfile); err != nil {
+		t.Fatal(err)
+	}
+	PlyPutElementSetup(plyfile, "vertex")
+	return plyfile, filename
+}
@@ -0,0 +1,66 @@
+package plyReaderRealsense
+
+import (
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+)
+
+/* PlyGetTextureFile returns the name of the texture image given in the header by a "comment TextureFile" line, "" if there is none */
+func PlyGetTextureFile(plyfile *PlyFile) string {
+	for _, comment := range plyfile.comments {
+		split := strings.Fields(comment)
+		if len(split) > 1 && split[0] == "TextureFile" {
+			return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(comment), "TextureFile"))
+		}
+	}
+	return ""
+}
+
+/* PlyLoadTexture decodes a PNG texture image */
+func PlyLoadTexture(filename string) (image.Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+/* PlySampleTexture returns the color of the texture at the coordinates (u, v), interpolated bilinearly between the 4 nearest pixels. As in the RealSense exports, (0, 0) is the top left corner of the image and (1, 1) the bottom right one, the coordinates outside are clamped to the border. */
+func PlySampleTexture(texture image.Image, u, v float64) (uint8, uint8, uint8) {
+	bounds := texture.Bounds()
+	if bounds.Empty() {
+		return 0, 0, 0
+	}
+
+	// position in pixels, the centers of the pixels being at half coordinates
+	x := u*float64(bounds.Dx()) - 0.5
+	y := v*float64(bounds.Dy()) - 0.5
+	x = math.Max(0, math.Min(float64(bounds.Dx()-1), x))
+	y = math.Max(0, math.Min(float64(bounds.Dy()-1), y))
+	x0, y0 := int(x), int(y)
+	x1, y1 := x0+1, y0+1
+	if x1 >= bounds.Dx() {
+		x1 = x0
+	}
+	if y1 >= bounds.Dy() {
+		y1 = y0
+	}
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	// mix the 4 pixels around the position
+	var rgb [3]float64
+	for _, corner := range []struct {
+		x, y   int
+		weight float64
+	}{{x0, y0, (1 - fx) * (1 - fy)}, {x1, y0, fx * (1 - fy)}, {x0, y1, (1 - fx) * fy}, {x1, y1, fx * fy}} {
+		r, g, b, _ := texture.At(bounds.Min.X+corner.x, bounds.Min.Y+corner.y).RGBA()
+		rgb[0] += corner.weight * float64(r)
+		rgb[1] += corner.weight * float64(g)
+		rgb[2] += corner.weight * float64(b)
+	}
+	return uint8(math.Round(rgb[0] / 257)), uint8(math.Round(rgb[1] / 257)), uint8(math.Round(rgb[2] / 257))
+}
@@ -0,0 +1,75 @@
+package plyReaderRealsense
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// testTexture is a 2x2 image : red, green on the top row, blue, white on the bottom one
+func testTexture() *image.RGBA {
+	texture := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	texture.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	texture.Set(1, 0, color.RGBA{0, 255, 0, 255})
+	texture.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	texture.Set(1, 1, color.RGBA{255, 255, 255, 255})
+	return texture
+}
+
+func TestPlySampleTexture(t *testing.T) {
+	tests := []struct {
+		name    string
+		u, v    float64
+		r, g, b uint8
+	}{
+		{"top left", 0, 0, 255, 0, 0},
+		{"top right", 1, 0, 0, 255, 0},
+		{"bottom left", 0, 1, 0, 0, 255},
+		{"bottom right", 1, 1, 255, 255, 255},
+		{"center of a pixel", 0.75, 0.25, 0, 255, 0},
+		{"centre blend", 0.5, 0.5, 128, 128, 128},
+		{"half way on the top row", 0.5, 0.25, 128, 128, 0},
+		{"clamped left and below", -1, 2, 0, 0, 255},
+		{"clamped right and above", 3, -0.5, 0, 255, 0},
+	}
+	texture := testTexture()
+	for _, test := range tests {
+		r, g, b := PlySampleTexture(texture, test.u, test.v)
+		if r != test.r || g != test.g || b != test.b {
+			t.Errorf("%s: (%g, %g) = %d %d %d, want %d %d %d", test.name, test.u, test.v, r, g, b, test.r, test.g, test.b)
+		}
+	}
+
+	// an image not starting at the origin and an empty one
+	if r, g, b := PlySampleTexture(texture.SubImage(image.Rect(1, 1, 2, 2)), 0.2, 0.9); r != 255 || g != 255 || b != 255 {
+		t.Errorf("sub image = %d %d %d", r, g, b)
+	}
+	if r, g, b := PlySampleTexture(image.NewRGBA(image.Rect(0, 0, 0, 0)), 0.5, 0.5); r != 0 || g != 0 || b != 0 {
+		t.Errorf("empty image = %d %d %d", r, g, b)
+	}
+}
+
+func TestPlyGetTextureFile(t *testing.T) {
+	tests := []struct {
+		comments string
+		want     string
+	}{
+		{"comment TextureFile texture.png\n", "texture.png"},
+		{"comment made by hand\ncomment TextureFile my texture.png \n", "my texture.png"},
+		{"comment TextureFile\n", ""},
+		{"comment Texture texture.png\n", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		header := "ply\nformat ascii 1.0\n" + test.comments + "element vertex 0\nproperty float x\nend_header\n"
+		plyfile, err := PlyOpenReader(strings.NewReader(header))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := PlyGetTextureFile(plyfile); name != test.want {
+			t.Errorf("%q: texture file %q, want %q", test.comments, name, test.want)
+		}
+		PlyClose(plyfile)
+	}
+}
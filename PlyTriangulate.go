@@ -0,0 +1,165 @@
+package plyReaderRealsense
+
+import "math"
+
+// triangulation methods turning polygons into triangles
+const (
+	PLY_TRIANGULATE_FAN = 1 /* triangles sharing the first vertex, exact for convex polygons */
+	PLY_TRIANGULATE_EAR = 2 /* ear clipping, also correct for concave polygons */
+)
+
+/* TriangulateFan32 splits each polygon into triangles sharing its first vertex. Polygons with less than 3 vertices are dropped. */
+func TriangulateFan32(polygons []Polygon32) []Face32 {
+	return plyTriangulateFan(polygons, func(a, b, c int32) Face32 { return Face32{a, b, c} })
+}
+
+/* TriangulateFan64 splits each polygon into triangles sharing its first vertex. Polygons with less than 3 vertices are dropped. */
+func TriangulateFan64(polygons []Polygon64) []Face64 {
+	return plyTriangulateFan(polygons, func(a, b, c int64) Face64 { return Face64{a, b, c} })
+}
+
+/* TriangulateFan splits each polygon into triangles sharing its first vertex, with the width of the indices chosen by the caller as in ReadPLY. Polygons with less than 3 vertices are dropped. */
+func TriangulateFan[I PlyInt](polygons [][]I) []Tri[I] {
+	return plyTriangulateFan(polygons, func(a, b, c I) Tri[I] { return Tri[I]{a, b, c} })
+}
+
+// plyTriangulateFan makes the fan of each polygon, face building a triangle of the wanted type from its 3 indices
+func plyTriangulateFan[I PlyInt, P ~[]I, F any](polygons []P, face func(a, b, c I) F) []F {
+	var faces []F
+	for _, polygon := range polygons {
+		for k := 1; k+1 < len(polygon); k++ {
+			faces = append(faces, face(polygon[0], polygon[k], polygon[k+1]))
+		}
+	}
+	return faces
+}
+
+/* TriangulateEar32 splits each polygon into triangles by ear clipping, after projecting it on its plane. A polygon referring to a missing vertex is split as a fan. */
+func TriangulateEar32(vertices []VertexMono, polygons []Polygon32) []Face32 {
+	var faces []Face32
+	for _, polygon := range polygons {
+		corners := make([][3]float64, 0, len(polygon))
+		for _, index := range polygon {
+			if index < 0 || int(index) >= len(vertices) {
+				corners = nil
+				break
+			}
+			v := vertices[index]
+			corners = append(corners, [3]float64{float64(v.X), float64(v.Y), float64(v.Z)})
+		}
+		for _, t := range plyEarClip(corners, len(polygon)) {
+			faces = append(faces, Face32{polygon[t[0]], polygon[t[1]], polygon[t[2]]})
+		}
+	}
+	return faces
+}
+
+/* TriangulateEar64 splits each polygon into triangles by ear clipping, after projecting it on its plane. A polygon referring to a missing vertex is split as a fan. */
+func TriangulateEar64(vertices []VertexMono64, polygons []Polygon64) []Face64 {
+	var faces []Face64
+	for _, polygon := range polygons {
+		corners := make([][3]float64, 0, len(polygon))
+		for _, index := range polygon {
+			if index < 0 || index >= int64(len(vertices)) {
+				corners = nil
+				break
+			}
+			v := vertices[index]
+			corners = append(corners, [3]float64{v.X, v.Y, v.Z})
+		}
+		for _, t := range plyEarClip(corners, len(polygon)) {
+			faces = append(faces, Face64{polygon[t[0]], polygon[t[1]], polygon[t[2]]})
+		}
+	}
+	return faces
+}
+
+// plyEarClip triangulates a polygon of n corners, returning the triangles as positions in the polygon. Without the coordinates of the corners it falls back to a fan.
+func plyEarClip(corners [][3]float64, n int) [][3]int {
+	var triangles [][3]int
+	if n < 3 {
+		return nil
+	}
+	if len(corners) != n || n == 3 {
+		for k := 1; k+1 < n; k++ {
+			triangles = append(triangles, [3]int{0, k, k + 1})
+		}
+		return triangles
+	}
+
+	// project the polygon on the plane of its largest extent, given by the normal of Newell
+	var normal [3]float64
+	for i := 0; i < n; i++ {
+		a, b := corners[i], corners[(i+1)%n]
+		normal[0] += (a[1] - b[1]) * (a[2] + b[2])
+		normal[1] += (a[2] - b[2]) * (a[0] + b[0])
+		normal[2] += (a[0] - b[0]) * (a[1] + b[1])
+	}
+	u, v := 0, 1
+	if math.Abs(normal[0]) > math.Abs(normal[1]) && math.Abs(normal[0]) > math.Abs(normal[2]) {
+		u, v = 1, 2
+	} else if math.Abs(normal[1]) > math.Abs(normal[2]) {
+		u, v = 2, 0
+	}
+	xs, ys := make([]float64, n), make([]float64, n)
+	area := 0.
+	for i := 0; i < n; i++ {
+		xs[i], ys[i] = corners[i][u], corners[i][v]
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += xs[i]*ys[j] - xs[j]*ys[i]
+	}
+	orientation := 1.
+	if area < 0 {
+		orientation = -1
+	}
+
+	// cut the ears one after another
+	remaining := make([]int, n)
+	for i := range remaining {
+		remaining[i] = i
+	}
+	for len(remaining) > 3 {
+		found := false
+		for k := 0; k < len(remaining); k++ {
+			a, b, c := remaining[(k+len(remaining)-1)%len(remaining)], remaining[k], remaining[(k+1)%len(remaining)]
+			if plyCross(xs, ys, a, b, c)*orientation <= 0 {
+				continue
+			}
+			ear := true
+			for _, p := range remaining {
+				if p != a && p != b && p != c && plyInTriangle(xs, ys, p, a, b, c, orientation) {
+					ear = false
+					break
+				}
+			}
+			if !ear {
+				continue
+			}
+			triangles = append(triangles, [3]int{a, b, c})
+			remaining = append(remaining[:k], remaining[k+1:]...)
+			found = true
+			break
+		}
+
+		// degenerate polygon : finish with a fan
+		if !found {
+			for k := 1; k+1 < len(remaining); k++ {
+				triangles = append(triangles, [3]int{remaining[0], remaining[k], remaining[k+1]})
+			}
+			return triangles
+		}
+	}
+	return append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+}
+
+// plyCross returns the z component of the cross product of ab and bc
+func plyCross(xs, ys []float64, a, b, c int) float64 {
+	return (xs[b]-xs[a])*(ys[c]-ys[b]) - (ys[b]-ys[a])*(xs[c]-xs[b])
+}
+
+// plyInTriangle tells whether the point p is inside or on the border of the triangle abc of the given orientation
+func plyInTriangle(xs, ys []float64, p, a, b, c int, orientation float64) bool {
+	return plyCross(xs, ys, a, b, p)*orientation >= 0 && plyCross(xs, ys, b, c, p)*orientation >= 0 && plyCross(xs, ys, c, a, p)*orientation >= 0
+}
@@ -1,9 +1,8 @@
 package plyReaderRealsense
 
 import (
-	"dataprocessing/mymath"
 	"dataprocessing/plyfile"
-	"encoding/binary"
+	"io"
 	"math/rand"
 	"time"
 )
@@ -12,131 +11,213 @@ var r = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 
 
-// read a monochrome .ply file, for 32 bits data and 64 bits data
+// read a monochrome .ply file, for 32 bits data and 64 bits data. The data are decoded following the format of the header : ascii, binary little endian or binary big endian. Faces with more than 3 vertices are split into triangles sharing their first vertex, ReadPLYPolygon32 and ReadPLYPolygon64 keeping them whole
 func ReadPLYMono64(filename string) ([]plyfile.VertexMono64, []plyfile.Face64) {
-	var vertices []plyfile.VertexMono64
-	var faces []plyfile.Face64
-
 	// open the PLY file for reading
-	cplyfile, elem_names := plyfile.PlyOpenForReading(filename)
-
-	// read each element
-	for _, name := range elem_names {
+	cplyfile, _ := plyfile.PlyOpenForReading(filename)
 
-		// get element description
-		_, num_elems, _ := plyfile.PlyGetElementDescription(cplyfile, name)
+	vertices, faces, _ := readPLYMono64(cplyfile)
 
-		if name == "vertex" {
-			// read all the vertices
-			vlisthuge := make([]float32, num_elems * 3)
-			plyfile.PlyGetElementHuge(cplyfile, &vlisthuge, len(vlisthuge) * 4)
-			for i := 0; i < num_elems; i++ {
-				var buff plyfile.VertexMono64
-				buff.X, buff.Y, buff.Z = float64(vlisthuge[i * 3]), float64(vlisthuge[i * 3 + 1]), float64(vlisthuge[i * 3 + 2])
-				vertices = append(vertices, buff)
-			}
-		} else if name == "face" {
-			// read all the faces
-			flisthuge := make([]byte, num_elems * 25)
-			_, _ = cplyfile.Fp.Read(flisthuge)
-
-			// decode the integers from the memory
-			for i := 0; i < num_elems; i++ {
-				var buff plyfile.Face64
-				buff.X, buff.Y, buff.Z = int64(binary.LittleEndian.Uint64(flisthuge[i * 25 + 1 : i * 25 + 9])), int64(binary.LittleEndian.Uint64(flisthuge[i * 25 + 9 : i * 25 + 17])), int64(binary.LittleEndian.Uint64(flisthuge[i * 25 + 17 : i * 25 + 25]))
-				faces = append(faces, buff)
-			}
-		}
+	// close the PLY file
+	plyfile.PlyClose(cplyfile)
+	return vertices, faces
+}
+func ReadPLYMono32(filename string) ([]plyfile.VertexMono, []plyfile.Face32) {
+	// open the PLY file for reading
+	cplyfile, _ := plyfile.PlyOpenForReading(filename)
 
+	vertices, faces, _ := readPLYMono32(cplyfile)
 
-	}
 	// close the PLY file
 	plyfile.PlyClose(cplyfile)
 	return vertices, faces
 }
-func ReadPLYMono32(filename string) ([]plyfile.VertexMono, []plyfile.Face32) {
-	var vertices []plyfile.VertexMono
-	var faces []plyfile.Face32
 
-	// open the PLY file for reading
-	cplyfile, elem_names := plyfile.PlyOpenForReading(filename)
+// ReadPLYMono64E and ReadPLYMono32E read a monochrome .ply file like ReadPLYMono64 and ReadPLYMono32, but return an error instead of stopping the program or ignoring it
+func ReadPLYMono64E(filename string) ([]plyfile.VertexMono64, []plyfile.Face64, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
 
-	// read each element
-	for _, name := range elem_names {
+	return readPLYMono64(cplyfile)
+}
+func ReadPLYMono32E(filename string) ([]plyfile.VertexMono, []plyfile.Face32, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readPLYMono32(cplyfile)
+}
+
+// ReadPLYMono64Parallel and ReadPLYMono32Parallel read a monochrome .ply file like ReadPLYMono64E and ReadPLYMono32E, decoding the binary data with the given number of goroutines (0 for GOMAXPROCS, 1 to decode sequentially)
+func ReadPLYMono64Parallel(filename string, workers int) ([]plyfile.VertexMono64, []plyfile.Face64, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	plyfile.PlySetConcurrency(cplyfile, workers)
+	return readPLYMono64(cplyfile)
+}
+func ReadPLYMono32Parallel(filename string, workers int) ([]plyfile.VertexMono, []plyfile.Face32, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
 
-		// get element description
+	plyfile.PlySetConcurrency(cplyfile, workers)
+	return readPLYMono32(cplyfile)
+}
+
+// ReadPLYMono64Reader and ReadPLYMono32Reader read a monochrome PLY content from a stream instead of a file on disk
+func ReadPLYMono64Reader(r io.Reader) ([]plyfile.VertexMono64, []plyfile.Face64, error) {
+	cplyfile, err := plyfile.PlyOpenReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readPLYMono64(cplyfile)
+}
+func ReadPLYMono32Reader(r io.Reader) ([]plyfile.VertexMono, []plyfile.Face32, error) {
+	cplyfile, err := plyfile.PlyOpenReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readPLYMono32(cplyfile)
+}
+
+// read a monochrome .ply file with the widths chosen by the caller for the coordinates and the indices, as in ReadPLY[float32, int32]("./example.ply")
+func ReadPLY[F plyfile.PlyFloat, I plyfile.PlyInt](filename string) ([]plyfile.Vec3[F], []plyfile.Tri[I], error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readElements(cplyfile, vec3[F], tri[I])
+}
+
+func readPLYMono64(cplyfile *plyfile.PlyFile) ([]plyfile.VertexMono64, []plyfile.Face64, error) {
+	return readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexMono64, error) { return it.Vertex64(), nil }, triangle64)
+}
+func readPLYMono32(cplyfile *plyfile.PlyFile) ([]plyfile.VertexMono, []plyfile.Face32, error) {
+	return readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexMono, error) { return it.Vertex(), nil }, triangle32)
+}
+
+// readElements fills the slices of vertices and faces through the iterators, so that only one chunk of the file is decoded at a time. vertex builds the value returned for each vertex, or stops the reading with an error, face appends the values built from the current face, none, one or several.
+func readElements[V any, T any](cplyfile *plyfile.PlyFile, vertex func(*plyfile.VertexIterator) (V, error), face func(*plyfile.FaceIterator, []T) []T) ([]V, []T, error) {
+	var vertices []V
+	var faces []T
+
+	// read the elements in the order of the file, the iterators skip the other elements
+	for _, name := range plyfile.PlyGetElementNames(cplyfile) {
 		_, num_elems, _ := plyfile.PlyGetElementDescription(cplyfile, name)
 
 		if name == "vertex" {
-			// read all the vertices
-			vlisthuge := make([]float32, num_elems * 3)
-			plyfile.PlyGetElementHuge(cplyfile, &vlisthuge, len(vlisthuge) * 4)
-			for i := 0; i < num_elems; i++ {
-				var buff plyfile.VertexMono
-				buff.X, buff.Y, buff.Z = vlisthuge[i * 3], vlisthuge[i * 3 + 1], vlisthuge[i * 3 + 2]
-				vertices = append(vertices, buff)
+			vertices = make([]V, 0, num_elems)
+			it := cplyfile.Vertices()
+			for it.Next() {
+				v, err := vertex(it)
+				if err != nil {
+					return vertices, faces, err
+				}
+				vertices = append(vertices, v)
+			}
+			if it.Err() != nil {
+				return vertices, faces, it.Err()
 			}
 		} else if name == "face" {
-			// read all the faces
-			flisthuge := make([]byte, num_elems * 13)
-			_, _ = cplyfile.Fp.Read(flisthuge)
-
-			// decode the integers from the memory
-			for i := 0; i < num_elems; i++ {
-				var buff plyfile.Face32
-				buff.X, buff.Y, buff.Z = int32(binary.LittleEndian.Uint32(flisthuge[i * 13 + 1 : i * 13 + 5])), int32(binary.LittleEndian.Uint32(flisthuge[i * 13 + 5 : i * 13 + 9])), int32(binary.LittleEndian.Uint32(flisthuge[i * 13 + 9 : i * 13 + 13]))
-				faces = append(faces, buff)
+			faces = make([]T, 0, num_elems)
+			it := cplyfile.Faces()
+			for it.Next() {
+				faces = face(it, faces)
+			}
+			if it.Err() != nil {
+				return vertices, faces, it.Err()
 			}
 		}
-		//for i := 0; i < num_props; i++ {
-		//	fmt.Println("property", plist[i].Name)
-		//}
 	}
-	// close the PLY file
-	plyfile.PlyClose(cplyfile)
-	return vertices, faces
+	return vertices, faces, nil
 }
 
+// vec3 returns the current vertex with the width of F
+func vec3[F plyfile.PlyFloat](it *plyfile.VertexIterator) (plyfile.Vec3[F], error) {
+	v := it.Vertex64()
+	return plyfile.Vec3[F]{X: F(v.X), Y: F(v.Y), Z: F(v.Z)}, nil
+}
 
-// AddNoise add noise to a given percentage of the total points, for 32 bits data and 64 bits data
-func AddNoise32(vertices []plyfile.VertexMono, percent float64, minNoise float64, maxNoise float64) {
-	// determine on which vertices to add the noise
-	var ListIndex []int
-	for i := 0; i < int(percent * float64(len(vertices))); i++ {
-		index := r.Intn(len(vertices) - 1)
-		if mymath.ExistIntList(ListIndex, index) {
-			i--
-			continue
-		} else {
-			ListIndex = append(ListIndex, index)
-		}
+// tri, triangle32 and triangle64 append the current face split into triangles sharing its first vertex, as TriangulateFan32 and TriangulateFan64 do, the faces with less than 3 vertices being dropped
+func tri[I plyfile.PlyInt](it *plyfile.FaceIterator, faces []plyfile.Tri[I]) []plyfile.Tri[I] {
+	list := it.Indices()
+	if len(list) == 3 {
+		return append(faces, plyfile.Tri[I]{X: I(list[0]), Y: I(list[1]), Z: I(list[2])})
 	}
-
-	// multiply a noise between ]minNoise, maxNoise[
-	for _, i := range ListIndex {
-		vertices[i].X *= float32(minNoise + r.Float64() * (maxNoise - minNoise))
-		vertices[i].Y *= float32(minNoise + r.Float64() * (maxNoise - minNoise))
-		vertices[i].Z *= float32(minNoise + r.Float64() * (maxNoise - minNoise))
+	polygon := make([]I, len(list))
+	for k := range list {
+		polygon[k] = I(list[k])
 	}
+	return append(faces, plyfile.TriangulateFan([][]I{polygon})...)
+}
+func triangle64(it *plyfile.FaceIterator, faces []plyfile.Face64) []plyfile.Face64 {
+	if len(it.Indices()) == 3 {
+		return append(faces, it.Face64())
+	}
+	return append(faces, plyfile.TriangulateFan64([]plyfile.Polygon64{it.Polygon64()})...)
+}
+func triangle32(it *plyfile.FaceIterator, faces []plyfile.Face32) []plyfile.Face32 {
+	if len(it.Indices()) == 3 {
+		return append(faces, it.Face())
+	}
+	return append(faces, plyfile.TriangulateFan32([]plyfile.Polygon32{it.Polygon()})...)
+}
+
+// AddNoise add noise to a given percentage of the total points, for 32 bits data and 64 bits data
+func AddNoise32(vertices []plyfile.VertexMono, percent float64, minNoise float64, maxNoise float64) {
+	addNoise(len(vertices), percent, minNoise, maxNoise, func(i int, nx, ny, nz float64) {
+		vertices[i].X *= float32(nx)
+		vertices[i].Y *= float32(ny)
+		vertices[i].Z *= float32(nz)
+	})
 }
 func AddNoise64(vertices []plyfile.VertexMono64, percent float64, minNoise float64, maxNoise float64) {
-	// determine on which vertices to add the noise
-	var ListIndex []int
-	for i := 0; i < int(percent * float64(len(vertices))); i++ {
-		index := r.Intn(len(vertices) - 1)
-		if mymath.ExistIntList(ListIndex, index) {
-			i--
-			continue
-		} else {
-			ListIndex = append(ListIndex, index)
-		}
+	addNoise(len(vertices), percent, minNoise, maxNoise, func(i int, nx, ny, nz float64) {
+		vertices[i].X *= nx
+		vertices[i].Y *= ny
+		vertices[i].Z *= nz
+	})
+}
+
+// AddNoise add noise to a given percentage of the total points, for the vertices of any width read by ReadPLY
+func AddNoise[F plyfile.PlyFloat](vertices []plyfile.Vec3[F], percent float64, minNoise float64, maxNoise float64) {
+	addNoise(len(vertices), percent, minNoise, maxNoise, func(i int, nx, ny, nz float64) {
+		vertices[i].X *= F(nx)
+		vertices[i].Y *= F(ny)
+		vertices[i].Z *= F(nz)
+	})
+}
+
+// addNoise chooses the points to move among n and calls scale with the noise to multiply each coordinate by
+func addNoise(n int, percent float64, minNoise float64, maxNoise float64, scale func(i int, nx, ny, nz float64)) {
+	// determine on which vertices to add the noise, each one chosen once among all n
+	count := int(percent * float64(n))
+	if count > n {
+		count = n
 	}
+	if count < 0 {
+		count = 0
+	}
+	ListIndex := r.Perm(n)[:count]
 
 	// multiply a noise between ]minNoise, maxNoise[
 	for _, i := range ListIndex {
-		vertices[i].X *= minNoise + r.Float64() * (maxNoise - minNoise)
-		vertices[i].Y *= minNoise + r.Float64() * (maxNoise - minNoise)
-		vertices[i].Z *= minNoise + r.Float64() * (maxNoise - minNoise)
+		nx := minNoise + r.Float64() * (maxNoise - minNoise)
+		ny := minNoise + r.Float64() * (maxNoise - minNoise)
+		nz := minNoise + r.Float64() * (maxNoise - minNoise)
+		scale(i, nx, ny, nz)
 	}
 }
-
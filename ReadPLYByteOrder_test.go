@@ -0,0 +1,55 @@
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"dataprocessing/plyfile"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeOrderedFile writes 2 vertices and 2 triangles in the binary format of the byte order
+func writeOrderedFile(t *testing.T, format string, order binary.ByteOrder) string {
+	t.Helper()
+	var data bytes.Buffer
+	data.WriteString("ply\nformat " + format + " 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nelement face 2\nproperty list uchar int vertex_indices\nend_header\n")
+	binary.Write(&data, order, []float32{1, 2, 3, -4, 5.5, 6})
+	for _, face := range [][]int32{{0, 1, 70000}, {1, 0, 2}} {
+		binary.Write(&data, order, uint8(3))
+		binary.Write(&data, order, face)
+	}
+	filename := filepath.Join(t.TempDir(), format+".ply")
+	if err := os.WriteFile(filename, data.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadPLYMonoByteOrder(t *testing.T) {
+	want_vertices := []plyfile.VertexMono{{X: 1, Y: 2, Z: 3}, {X: -4, Y: 5.5, Z: 6}}
+	want_faces := []plyfile.Face32{{X: 0, Y: 1, Z: 70000}, {X: 1, Y: 0, Z: 2}}
+	tests := []struct {
+		format string
+		order  binary.ByteOrder
+	}{
+		{"binary_little_endian", binary.LittleEndian},
+		{"binary_big_endian", binary.BigEndian},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			filename := writeOrderedFile(t, test.format, test.order)
+			vertices, faces, err := ReadPLYMono32E(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(vertices, want_vertices) || !reflect.DeepEqual(faces, want_faces) {
+				t.Errorf("read %v %v, want %v %v", vertices, faces, want_vertices, want_faces)
+			}
+			if _, faces64 := ReadPLYMono64(filename); len(faces64) != 2 || faces64[0].Z != 70000 {
+				t.Errorf("ReadPLYMono64 faces = %v", faces64)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"errors"
+)
+
+// read a colored .ply file (red, green and blue properties in any order, as exported by the RealSense Viewer), for 32 bits data and 64 bits data. The third return holds the alpha of each vertex, nil if the file has no alpha.
+func ReadPLYColor64(filename string) ([]plyfile.Vertex64, []plyfile.Face64, []uint8, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	var alpha []uint8
+	vertices, faces, err := readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.Vertex64, error) {
+		if !it.HasColor() {
+			return plyfile.Vertex64{}, errNoColor
+		}
+		if it.HasAlpha() {
+			alpha = append(alpha, it.Alpha())
+		}
+		return it.VertexColor64(), nil
+	}, triangle64)
+	return vertices, faces, alpha, err
+}
+func ReadPLYColor32(filename string) ([]plyfile.Vertex, []plyfile.Face32, []uint8, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	var alpha []uint8
+	vertices, faces, err := readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.Vertex, error) {
+		if !it.HasColor() {
+			return plyfile.Vertex{}, errNoColor
+		}
+		if it.HasAlpha() {
+			alpha = append(alpha, it.Alpha())
+		}
+		return it.VertexColor(), nil
+	}, triangle32)
+	return vertices, faces, alpha, err
+}
+
+var errNoColor = errors.New("vertex without red, green and blue properties")
@@ -0,0 +1,89 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeColorFile writes an ascii file of 2 vertices with the given properties and values, and one triangle
+func writeColorFile(t *testing.T, props string, values string) string {
+	t.Helper()
+	content := "ply\nformat ascii 1.0\nelement vertex 2\n" + props + "element face 1\nproperty list uchar int vertex_indices\nend_header\n" + values + "3 0 1 1\n"
+	filename := filepath.Join(t.TempDir(), "color.ply")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadPLYColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    string
+		values   string
+		vertices []plyfile.Vertex
+		alpha    []uint8
+	}{
+		{
+			"default order",
+			"property float x\nproperty float y\nproperty float z\nproperty uchar red\nproperty uchar green\nproperty uchar blue\n",
+			"1 2 3 10 20 30\n4 5 6 40 50 60\n",
+			[]plyfile.Vertex{{X: 1, Y: 2, Z: 3, R: 10, G: 20, B: 30}, {X: 4, Y: 5, Z: 6, R: 40, G: 50, B: 60}},
+			nil,
+		},
+		{
+			"other order with alpha",
+			"property uchar blue\nproperty float x\nproperty uchar alpha\nproperty uchar red\nproperty float y\nproperty uchar green\nproperty float z\n",
+			"30 1 255 10 2 20 3\n60 4 128 40 5 50 6\n",
+			[]plyfile.Vertex{{X: 1, Y: 2, Z: 3, R: 10, G: 20, B: 30}, {X: 4, Y: 5, Z: 6, R: 40, G: 50, B: 60}},
+			[]uint8{255, 128},
+		},
+		{
+			"float colors",
+			"property float x\nproperty float y\nproperty float z\nproperty float red\nproperty double green\nproperty float blue\n",
+			"1 2 3 1 0.5 0\n4 5 6 1.5 -0.2 0.1\n",
+			[]plyfile.Vertex{{X: 1, Y: 2, Z: 3, R: 255, G: 128, B: 0}, {X: 4, Y: 5, Z: 6, R: 255, G: 0, B: 26}},
+			nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := writeColorFile(t, test.props, test.values)
+			want_faces := []plyfile.Face32{{X: 0, Y: 1, Z: 1}}
+
+			vertices, faces, alpha, err := ReadPLYColor32(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(vertices, test.vertices) || !reflect.DeepEqual(faces, want_faces) || !reflect.DeepEqual(alpha, test.alpha) {
+				t.Errorf("ReadPLYColor32 = %v %v %v, want %v %v %v", vertices, faces, alpha, test.vertices, want_faces, test.alpha)
+			}
+
+			vertices64, faces64, alpha, err := ReadPLYColor64(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want64 := make([]plyfile.Vertex64, len(test.vertices))
+			for i, v := range test.vertices {
+				want64[i] = plyfile.Vertex64{X: float64(v.X), Y: float64(v.Y), Z: float64(v.Z), R: v.R, G: v.G, B: v.B}
+			}
+			if !reflect.DeepEqual(vertices64, want64) || !reflect.DeepEqual(faces64, toFace64(want_faces)) || !reflect.DeepEqual(alpha, test.alpha) {
+				t.Errorf("ReadPLYColor64 = %v %v %v, want %v %v", vertices64, faces64, alpha, want64, test.alpha)
+			}
+		})
+	}
+}
+
+func TestReadPLYColorWithoutColor(t *testing.T) {
+	filename := writeColorFile(t, "property float x\nproperty float y\nproperty float z\nproperty uchar red\n", "1 2 3 4\n5 6 7 8\n")
+	if _, _, _, err := ReadPLYColor32(filename); !errors.Is(err, errNoColor) {
+		t.Errorf("ReadPLYColor32 err = %v, want %v", err, errNoColor)
+	}
+	if _, _, _, err := ReadPLYColor64(filename); !errors.Is(err, errNoColor) {
+		t.Errorf("ReadPLYColor64 err = %v, want %v", err, errNoColor)
+	}
+}
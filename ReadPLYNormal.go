@@ -0,0 +1,25 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+)
+
+// read a .ply file with the normal of each vertex, for 32 bits data and 64 bits data. The normals are zero if the header has no nx, ny and nz properties.
+func ReadPLYNormal64(filename string) ([]plyfile.VertexNormal64, []plyfile.Face64, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexNormal64, error) { return it.VertexNormal64(), nil }, triangle64)
+}
+func ReadPLYNormal32(filename string) ([]plyfile.VertexNormal, []plyfile.Face32, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexNormal, error) { return it.VertexNormal(), nil }, triangle32)
+}
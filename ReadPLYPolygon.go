@@ -0,0 +1,62 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"fmt"
+)
+
+// read a .ply file whose faces may have any number of vertices (quads, n-gons...), for 32 bits data and 64 bits data
+func ReadPLYPolygon64(filename string) ([]plyfile.VertexMono64, []plyfile.Polygon64, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexMono64, error) { return it.Vertex64(), nil },
+		func(it *plyfile.FaceIterator, polygons []plyfile.Polygon64) []plyfile.Polygon64 {
+			return append(polygons, it.Polygon64())
+		})
+}
+func ReadPLYPolygon32(filename string) ([]plyfile.VertexMono, []plyfile.Polygon32, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexMono, error) { return it.Vertex(), nil },
+		func(it *plyfile.FaceIterator, polygons []plyfile.Polygon32) []plyfile.Polygon32 {
+			return append(polygons, it.Polygon())
+		})
+}
+
+// read a .ply file and split its polygons into triangles with the given method (PLY_TRIANGULATE_FAN or PLY_TRIANGULATE_EAR), for 32 bits data and 64 bits data
+func ReadPLYTriangulated64(filename string, method int) ([]plyfile.VertexMono64, []plyfile.Face64, error) {
+	vertices, polygons, err := ReadPLYPolygon64(filename)
+	if err != nil {
+		return vertices, nil, err
+	}
+
+	switch method {
+	case plyfile.PLY_TRIANGULATE_FAN:
+		return vertices, plyfile.TriangulateFan64(polygons), nil
+	case plyfile.PLY_TRIANGULATE_EAR:
+		return vertices, plyfile.TriangulateEar64(vertices, polygons), nil
+	}
+	return vertices, nil, fmt.Errorf("unknown triangulation method %d", method)
+}
+func ReadPLYTriangulated32(filename string, method int) ([]plyfile.VertexMono, []plyfile.Face32, error) {
+	vertices, polygons, err := ReadPLYPolygon32(filename)
+	if err != nil {
+		return vertices, nil, err
+	}
+
+	switch method {
+	case plyfile.PLY_TRIANGULATE_FAN:
+		return vertices, plyfile.TriangulateFan32(polygons), nil
+	case plyfile.PLY_TRIANGULATE_EAR:
+		return vertices, plyfile.TriangulateEar32(vertices, polygons), nil
+	}
+	return vertices, nil, fmt.Errorf("unknown triangulation method %d", method)
+}
@@ -0,0 +1,111 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// a square cut in a triangle, a quad and a pentagon, with a degenerate face of 2 vertices
+const polygonPLY = `ply
+format ascii 1.0
+element vertex 6
+property float x
+property float y
+property float z
+element face 4
+property list uchar int vertex_indices
+end_header
+0 0 0
+1 0 0
+1 1 0
+0 1 0
+0.5 1.5 0
+-0.5 0.5 0
+3 0 1 2
+4 0 1 2 3
+5 0 1 4 3 5
+2 0 1
+`
+
+// writePolygonFile writes polygonPLY in a temporary directory
+func writePolygonFile(t *testing.T) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "polygon.ply")
+	if err := os.WriteFile(filename, []byte(polygonPLY), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadPolygonFaces(t *testing.T) {
+	filename := writePolygonFile(t)
+	var fan []plyfile.Face32
+	for _, f := range [][3]int32{{0, 1, 2}, {0, 1, 2}, {0, 2, 3}, {0, 1, 4}, {0, 4, 3}, {0, 3, 5}} {
+		fan = append(fan, plyfile.Face32{X: f[0], Y: f[1], Z: f[2]})
+	}
+
+	_, polygons, err := ReadPLYPolygon32(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []plyfile.Polygon32{{0, 1, 2}, {0, 1, 2, 3}, {0, 1, 4, 3, 5}, {0, 1}}
+	if !reflect.DeepEqual(polygons, want) {
+		t.Errorf("ReadPLYPolygon32 = %v, want %v", polygons, want)
+	}
+
+	tests := []struct {
+		name  string
+		read  func() (interface{}, error)
+		faces interface{}
+	}{
+		{"ReadPLYMono32E", func() (interface{}, error) { _, f, err := ReadPLYMono32E(filename); return f, err }, fan},
+		{"ReadPLYMono64E", func() (interface{}, error) { _, f, err := ReadPLYMono64E(filename); return f, err }, toFace64(fan)},
+		{"ReadPLY", func() (interface{}, error) { _, f, err := ReadPLY[float32, int32](filename); return f, err }, toTri(fan)},
+		{"ReadPLYNormal32", func() (interface{}, error) { _, f, err := ReadPLYNormal32(filename); return f, err }, fan},
+		{"ReadPLYTriangulated32", func() (interface{}, error) {
+			_, f, err := ReadPLYTriangulated32(filename, plyfile.PLY_TRIANGULATE_FAN)
+			return f, err
+		}, fan},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			faces, err := test.read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(faces, test.faces) {
+				t.Errorf("faces = %v, want %v", faces, test.faces)
+			}
+		})
+	}
+}
+
+func TestReadTriangulatedEar(t *testing.T) {
+	_, faces, err := ReadPLYTriangulated64(writePolygonFile(t), plyfile.PLY_TRIANGULATE_EAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// n vertices give n-2 triangles
+	if len(faces) != 1+2+3 {
+		t.Errorf("%d triangles, want 6", len(faces))
+	}
+}
+
+func toFace64(faces []plyfile.Face32) []plyfile.Face64 {
+	out := make([]plyfile.Face64, len(faces))
+	for i, f := range faces {
+		out[i] = plyfile.Face64{X: int64(f.X), Y: int64(f.Y), Z: int64(f.Z)}
+	}
+	return out
+}
+
+func toTri(faces []plyfile.Face32) []plyfile.Tri[int32] {
+	out := make([]plyfile.Tri[int32], len(faces))
+	for i, f := range faces {
+		out[i] = plyfile.Tri[int32]{X: f.X, Y: f.Y, Z: f.Z}
+	}
+	return out
+}
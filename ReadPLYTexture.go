@@ -0,0 +1,112 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"errors"
+	"path/filepath"
+)
+
+// read a textured .ply file with the u, v coordinates of each vertex, for 32 bits data and 64 bits data
+func ReadPLYTexture64(filename string) ([]plyfile.VertexMono64, []plyfile.TexCoord64, []plyfile.Face64, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readPLYTexture64(cplyfile)
+}
+func ReadPLYTexture32(filename string) ([]plyfile.VertexMono, []plyfile.TexCoord, []plyfile.Face32, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	return readPLYTexture32(cplyfile)
+}
+
+// read a textured .ply file and color each vertex by sampling the texture image bilinearly at its u, v coordinates, for 32 bits data and 64 bits data. If texture is "", the PNG image named by the "comment TextureFile" line of the header is used, relative to the directory of the .ply file.
+func ReadPLYTextured64(filename string, texture string) ([]plyfile.Vertex64, []plyfile.Face64, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	image, err := plyfile.PlyLoadTexture(textureFile(cplyfile, filename, texture))
+	if err != nil {
+		return nil, nil, err
+	}
+	positions, uvs, faces, err := readPLYTexture64(cplyfile)
+	if err != nil {
+		return nil, faces, err
+	}
+
+	vertices := make([]plyfile.Vertex64, len(positions))
+	for i, p := range positions {
+		vertices[i].X, vertices[i].Y, vertices[i].Z = p.X, p.Y, p.Z
+		vertices[i].R, vertices[i].G, vertices[i].B = plyfile.PlySampleTexture(image, uvs[i].U, uvs[i].V)
+	}
+	return vertices, faces, nil
+}
+func ReadPLYTextured32(filename string, texture string) ([]plyfile.Vertex, []plyfile.Face32, error) {
+	cplyfile, err := plyfile.OpenPLY(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plyfile.PlyClose(cplyfile)
+
+	image, err := plyfile.PlyLoadTexture(textureFile(cplyfile, filename, texture))
+	if err != nil {
+		return nil, nil, err
+	}
+	positions, uvs, faces, err := readPLYTexture32(cplyfile)
+	if err != nil {
+		return nil, faces, err
+	}
+
+	vertices := make([]plyfile.Vertex, len(positions))
+	for i, p := range positions {
+		vertices[i].X, vertices[i].Y, vertices[i].Z = p.X, p.Y, p.Z
+		vertices[i].R, vertices[i].G, vertices[i].B = plyfile.PlySampleTexture(image, float64(uvs[i].U), float64(uvs[i].V))
+	}
+	return vertices, faces, nil
+}
+
+// textureFile returns the texture given by the caller, or the one named in the header next to the .ply file
+func textureFile(cplyfile *plyfile.PlyFile, filename string, texture string) string {
+	if texture != "" {
+		return texture
+	}
+	name := plyfile.PlyGetTextureFile(cplyfile)
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(filename), name)
+}
+
+func readPLYTexture64(cplyfile *plyfile.PlyFile) ([]plyfile.VertexMono64, []plyfile.TexCoord64, []plyfile.Face64, error) {
+	var uvs []plyfile.TexCoord64
+	vertices, faces, err := readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexMono64, error) {
+		if !it.HasUV() {
+			return plyfile.VertexMono64{}, errNoUV
+		}
+		uvs = append(uvs, it.UV64())
+		return it.Vertex64(), nil
+	}, triangle64)
+	return vertices, uvs, faces, err
+}
+func readPLYTexture32(cplyfile *plyfile.PlyFile) ([]plyfile.VertexMono, []plyfile.TexCoord, []plyfile.Face32, error) {
+	var uvs []plyfile.TexCoord
+	vertices, faces, err := readElements(cplyfile, func(it *plyfile.VertexIterator) (plyfile.VertexMono, error) {
+		if !it.HasUV() {
+			return plyfile.VertexMono{}, errNoUV
+		}
+		uvs = append(uvs, it.UV())
+		return it.Vertex(), nil
+	}, triangle32)
+	return vertices, uvs, faces, err
+}
+
+var errNoUV = errors.New("vertex without texture coordinates")
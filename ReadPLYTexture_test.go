@@ -0,0 +1,166 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// a textured export naming its texture, the vertices lying on the corners and the centre of the image
+const texturedPLY = `ply
+format ascii 1.0
+comment TextureFile texture.png
+element vertex 4
+property float x
+property float y
+property float z
+property float u
+property float v
+element face 2
+property list uchar int vertex_indices
+end_header
+0 0 0 0 0
+1 0 0 1 0
+0 1 0 0 1
+0.5 0.5 1 0.5 0.5
+3 0 1 3
+3 0 3 2
+`
+
+// writeTexturedFile writes texturedPLY and a 2x2 texture (red, green / blue, white) in a temporary directory, the texture under the given name
+func writeTexturedFile(t *testing.T, texture string) string {
+	t.Helper()
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "textured.ply")
+	if err := os.WriteFile(filename, []byte(texturedPLY), 0644); err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{0, 255, 0, 255})
+	img.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	img.Set(1, 1, color.RGBA{255, 255, 255, 255})
+	f, err := os.Create(filepath.Join(dir, texture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err = png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+var (
+	texturedFaces    = []plyfile.Face32{{X: 0, Y: 1, Z: 3}, {X: 0, Y: 3, Z: 2}}
+	texturedVertices = []plyfile.Vertex{
+		{X: 0, Y: 0, Z: 0, R: 255, G: 0, B: 0},
+		{X: 1, Y: 0, Z: 0, R: 0, G: 255, B: 0},
+		{X: 0, Y: 1, Z: 0, R: 0, G: 0, B: 255},
+		{X: 0.5, Y: 0.5, Z: 1, R: 128, G: 128, B: 128},
+	}
+)
+
+func TestReadPLYTexture(t *testing.T) {
+	filename := writeTexturedFile(t, "texture.png")
+	want_uvs := []plyfile.TexCoord{{U: 0, V: 0}, {U: 1, V: 0}, {U: 0, V: 1}, {U: 0.5, V: 0.5}}
+
+	vertices, uvs, faces, err := ReadPLYTexture32(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != 4 || vertices[3] != (plyfile.VertexMono{X: 0.5, Y: 0.5, Z: 1}) || !reflect.DeepEqual(uvs, want_uvs) || !reflect.DeepEqual(faces, texturedFaces) {
+		t.Errorf("ReadPLYTexture32 = %v %v %v", vertices, uvs, faces)
+	}
+
+	vertices64, uvs64, faces64, err := ReadPLYTexture64(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices64) != 4 || vertices64[3] != (plyfile.VertexMono64{X: 0.5, Y: 0.5, Z: 1}) || uvs64[1] != (plyfile.TexCoord64{U: 1, V: 0}) || !reflect.DeepEqual(faces64, toFace64(texturedFaces)) {
+		t.Errorf("ReadPLYTexture64 = %v %v %v", vertices64, uvs64, faces64)
+	}
+
+	// the vertices without u, v are refused
+	if _, _, _, err = ReadPLYTexture32(writePolygonFile(t)); err == nil {
+		t.Error("no error for vertices without texture coordinates")
+	}
+}
+
+func TestReadPLYTextured(t *testing.T) {
+	// the texture of the header is found next to the .ply file, whatever the working directory
+	filename := writeTexturedFile(t, "texture.png")
+	vertices, faces, err := ReadPLYTextured32(filename, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vertices, texturedVertices) || !reflect.DeepEqual(faces, texturedFaces) {
+		t.Errorf("ReadPLYTextured32 = %v %v, want %v %v", vertices, faces, texturedVertices, texturedFaces)
+	}
+
+	// the texture given by the caller replaces the one of the header
+	other := writeTexturedFile(t, "other.png")
+	vertices64, faces64, err := ReadPLYTextured64(other, filepath.Join(filepath.Dir(other), "other.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want64 := make([]plyfile.Vertex64, len(texturedVertices))
+	for i, v := range texturedVertices {
+		want64[i] = plyfile.Vertex64{X: float64(v.X), Y: float64(v.Y), Z: float64(v.Z), R: v.R, G: v.G, B: v.B}
+	}
+	if !reflect.DeepEqual(vertices64, want64) || !reflect.DeepEqual(faces64, toFace64(texturedFaces)) {
+		t.Errorf("ReadPLYTextured64 = %v %v, want %v", vertices64, faces64, want64)
+	}
+	if _, _, err = ReadPLYTextured64(other, ""); !os.IsNotExist(err) {
+		t.Errorf("err = %v for a missing texture", err)
+	}
+
+	// the colored vertices are written back and read again
+	out := filepath.Join(t.TempDir(), "colored.ply")
+	if err = WritePLYColor64(out, vertices64, faces64, plyfile.PLY_BINARY_LE); err != nil {
+		t.Fatal(err)
+	}
+	read_vertices, read_faces, _, err := ReadPLYColor64(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read_vertices, want64) || !reflect.DeepEqual(read_faces, faces64) {
+		t.Errorf("written back %v %v, want %v %v", read_vertices, read_faces, want64, faces64)
+	}
+}
+
+func TestTextureFile(t *testing.T) {
+	plyfile_path := filepath.Join("captures", "scan.ply")
+	absolute := filepath.Join(string(filepath.Separator), "textures", "scan.png")
+	tests := []struct {
+		header  string
+		texture string
+		want    string
+	}{
+		{"comment TextureFile scan.png\n", "", filepath.Join("captures", "scan.png")},
+		{"comment TextureFile ../textures/scan.png\n", "", filepath.Join("textures", "scan.png")},
+		{"comment TextureFile " + absolute + "\n", "", absolute},
+		{"comment TextureFile scan.png\n", "given.png", "given.png"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		filename := filepath.Join(t.TempDir(), "scan.ply")
+		content := "ply\nformat ascii 1.0\n" + test.header + "element vertex 0\nproperty float x\nend_header\n"
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cplyfile, err := plyfile.OpenPLY(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := textureFile(cplyfile, plyfile_path, test.texture); name != test.want {
+			t.Errorf("%q %q: texture %q, want %q", test.header, test.texture, name, test.want)
+		}
+		plyfile.PlyClose(cplyfile)
+	}
+}
@@ -0,0 +1,142 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// cloud exported by the RealSense Viewer the benchmarks decode
+const benchFile = "./example.ply"
+
+// readPLYMono32Baseline is ReadPLYMono32 as it was before the parallel decoding : one big read of each block, then a single-threaded loop appending to the slices. It reads the binary little endian files of the RealSense Viewer only.
+func readPLYMono32Baseline(filename string) ([]plyfile.VertexMono, []plyfile.Face32) {
+	var vertices []plyfile.VertexMono
+	var faces []plyfile.Face32
+
+	cplyfile, elem_names := plyfile.PlyOpenForReading(filename)
+	for _, name := range elem_names {
+		_, num_elems, _ := plyfile.PlyGetElementDescription(cplyfile, name)
+
+		if name == "vertex" {
+			vlisthuge := make([]float32, num_elems*3)
+			plyfile.PlyGetElementHuge(cplyfile, &vlisthuge, len(vlisthuge)*4)
+			for i := 0; i < num_elems; i++ {
+				var buff plyfile.VertexMono
+				buff.X, buff.Y, buff.Z = vlisthuge[i*3], vlisthuge[i*3+1], vlisthuge[i*3+2]
+				vertices = append(vertices, buff)
+			}
+		} else if name == "face" {
+			flisthuge := make([]byte, num_elems*13)
+			plyfile.PlyGetElementHuge(cplyfile, &flisthuge, len(flisthuge))
+			for i := 0; i < num_elems; i++ {
+				var buff plyfile.Face32
+				buff.X, buff.Y, buff.Z = int32(binary.LittleEndian.Uint32(flisthuge[i*13+1:i*13+5])), int32(binary.LittleEndian.Uint32(flisthuge[i*13+5:i*13+9])), int32(binary.LittleEndian.Uint32(flisthuge[i*13+9:i*13+13]))
+				faces = append(faces, buff)
+			}
+		}
+	}
+	plyfile.PlyClose(cplyfile)
+	return vertices, faces
+}
+
+// skipWithoutBenchFile skips when example.ply is not next to the tests
+func skipWithoutBenchFile(tb testing.TB) {
+	if _, err := os.Stat(benchFile); err != nil {
+		tb.Skip("no " + benchFile)
+	}
+}
+
+func TestReadPLYMono32MatchesBaseline(t *testing.T) {
+	skipWithoutBenchFile(t)
+	want_vertices, want_faces := readPLYMono32Baseline(benchFile)
+	for _, workers := range []int{1, 2, 4} {
+		vertices, faces, err := ReadPLYMono32Parallel(benchFile, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(vertices, want_vertices) || !reflect.DeepEqual(faces, want_faces) {
+			t.Errorf("%d workers: the data differ from the original decoding", workers)
+		}
+	}
+}
+
+func BenchmarkReadPLYMono32Baseline(b *testing.B) {
+	skipWithoutBenchFile(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		readPLYMono32Baseline(benchFile)
+	}
+}
+
+func BenchmarkReadPLYMono32(b *testing.B) {
+	skipWithoutBenchFile(b)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ReadPLYMono32Parallel(benchFile, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReadPLYMono64(b *testing.B) {
+	skipWithoutBenchFile(b)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ReadPLYMono64Parallel(benchFile, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestAddNoise(t *testing.T) {
+	// with a noise of exactly 2, a vertex chosen twice or left aside would not end doubled
+	for _, test := range []struct {
+		n       int
+		percent float64
+		moved   int
+	}{{1, 1, 1}, {2, 1, 2}, {10, 1, 10}, {10, 0.5, 5}, {10, 0, 0}, {0, 1, 0}, {5, 1.5, 5}} {
+		vertices := make([]plyfile.VertexMono64, test.n)
+		for i := range vertices {
+			vertices[i] = plyfile.VertexMono64{X: 1, Y: 1, Z: 1}
+		}
+		AddNoise64(vertices, test.percent, 2, 2)
+		moved := 0
+		for i, v := range vertices {
+			switch v {
+			case plyfile.VertexMono64{X: 2, Y: 2, Z: 2}:
+				moved++
+			case plyfile.VertexMono64{X: 1, Y: 1, Z: 1}:
+			default:
+				t.Errorf("n %d percent %g: vertex %d = %v", test.n, test.percent, i, v)
+			}
+		}
+		if moved != test.moved {
+			t.Errorf("n %d percent %g: %d vertices moved, want %d", test.n, test.percent, moved, test.moved)
+		}
+	}
+
+	// the last vertex is chosen as well as the others
+	last := 0
+	for k := 0; k < 200; k++ {
+		vertices := []plyfile.Vec3[float32]{{X: 1}, {X: 1}, {X: 1}, {X: 1}}
+		AddNoise(vertices, 0.25, 2, 2)
+		if vertices[3].X == 2 {
+			last++
+		}
+	}
+	if last == 0 {
+		t.Error("the last vertex is never moved")
+	}
+}
@@ -0,0 +1,205 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"fmt"
+	"math"
+)
+
+// write a monochrome .ply file in binary little endian format, as exported by the RealSense Viewer, for 32 bits data and 64 bits data. The whole slices are written at once through a buffer. The indices are written as int, the 64 bits indices out of its range are refused.
+func WritePLYMono64(filename string, vertices []plyfile.VertexMono64, faces []plyfile.Face64) error {
+	if err := checkIndices64(faces); err != nil {
+		return err
+	}
+	records := make([]plyfile.FaceReadingHuge, len(faces))
+	for i, f := range faces {
+		records[i] = plyfile.FaceReadingHuge{Nverts: 3, Vert1: int32(f.X), Vert2: int32(f.Y), Vert3: int32(f.Z)}
+	}
+	return writeMono(filename, plyfile.PLY_DOUBLE, vertices, len(vertices), records)
+}
+func WritePLYMono32(filename string, vertices []plyfile.VertexMono, faces []plyfile.Face32) error {
+	records := make([]plyfile.FaceReadingHuge, len(faces))
+	for i, f := range faces {
+		records[i] = plyfile.FaceReadingHuge{Nverts: 3, Vert1: f.X, Vert2: f.Y, Vert3: f.Z}
+	}
+	return writeMono(filename, plyfile.PLY_FLOAT, vertices, len(vertices), records)
+}
+
+// write a colored .ply file, with the red, green and blue of each vertex, in binary little endian or big endian format, for 32 bits data and 64 bits data. The 64 bits indices out of the range of int are refused.
+func WritePLYColor64(filename string, vertices []plyfile.Vertex64, faces []plyfile.Face64, file_type int) error {
+	if err := checkIndices64(faces); err != nil {
+		return err
+	}
+	vertex_data := newColumns(len(vertices), plyfile.PLY_DOUBLE, "x", "y", "z")
+	vertex_data = append(vertex_data, newColumns(len(vertices), plyfile.PLY_UCHAR, "red", "green", "blue")...)
+	for i, v := range vertices {
+		vertex_data[0].Values[i], vertex_data[1].Values[i], vertex_data[2].Values[i] = v.X, v.Y, v.Z
+		vertex_data[3].Values[i], vertex_data[4].Values[i], vertex_data[5].Values[i] = float64(v.R), float64(v.G), float64(v.B)
+	}
+	return writeColumns(filename, file_type, vertex_data, faceColumns64(faces))
+}
+func WritePLYColor32(filename string, vertices []plyfile.Vertex, faces []plyfile.Face32, file_type int) error {
+	vertex_data := newColumns(len(vertices), plyfile.PLY_FLOAT, "x", "y", "z")
+	vertex_data = append(vertex_data, newColumns(len(vertices), plyfile.PLY_UCHAR, "red", "green", "blue")...)
+	for i, v := range vertices {
+		vertex_data[0].Values[i], vertex_data[1].Values[i], vertex_data[2].Values[i] = float64(v.X), float64(v.Y), float64(v.Z)
+		vertex_data[3].Values[i], vertex_data[4].Values[i], vertex_data[5].Values[i] = float64(v.R), float64(v.G), float64(v.B)
+	}
+	return writeColumns(filename, file_type, vertex_data, faceColumns32(faces))
+}
+
+// write a .ply file with the normal of each vertex, in binary little endian or big endian format, for 32 bits data and 64 bits data. The 64 bits indices out of the range of int are refused.
+func WritePLYNormal64(filename string, vertices []plyfile.VertexNormal64, faces []plyfile.Face64, file_type int) error {
+	if err := checkIndices64(faces); err != nil {
+		return err
+	}
+	vertex_data := newColumns(len(vertices), plyfile.PLY_DOUBLE, "x", "y", "z", "nx", "ny", "nz")
+	for i, v := range vertices {
+		vertex_data[0].Values[i], vertex_data[1].Values[i], vertex_data[2].Values[i] = v.X, v.Y, v.Z
+		vertex_data[3].Values[i], vertex_data[4].Values[i], vertex_data[5].Values[i] = v.NX, v.NY, v.NZ
+	}
+	return writeColumns(filename, file_type, vertex_data, faceColumns64(faces))
+}
+func WritePLYNormal32(filename string, vertices []plyfile.VertexNormal, faces []plyfile.Face32, file_type int) error {
+	vertex_data := newColumns(len(vertices), plyfile.PLY_FLOAT, "x", "y", "z", "nx", "ny", "nz")
+	for i, v := range vertices {
+		vertex_data[0].Values[i], vertex_data[1].Values[i], vertex_data[2].Values[i] = float64(v.X), float64(v.Y), float64(v.Z)
+		vertex_data[3].Values[i], vertex_data[4].Values[i], vertex_data[5].Values[i] = float64(v.NX), float64(v.NY), float64(v.NZ)
+	}
+	return writeColumns(filename, file_type, vertex_data, faceColumns32(faces))
+}
+
+// newColumns prepares the columns of n values of scalar properties having the same type
+func newColumns(n int, typ int, names ...string) []plyfile.PlyPropertyData {
+	data := make([]plyfile.PlyPropertyData, len(names))
+	for j, name := range names {
+		data[j].Prop = *plyfile.New_property(name, typ, typ, 0, 0, 0, 0, 0)
+		data[j].Values = make([]float64, n)
+	}
+	return data
+}
+
+// checkIndices64 returns an error for the first index which does not fit the int the faces are written with
+func checkIndices64(faces []plyfile.Face64) error {
+	for i, f := range faces {
+		for _, index := range []int64{f.X, f.Y, f.Z} {
+			if index < math.MinInt32 || index > math.MaxInt32 {
+				return fmt.Errorf("face %d: vertex index %d out of the range of int", i, index)
+			}
+		}
+	}
+	return nil
+}
+
+// faceColumns32 and faceColumns64 put the faces in a list of uchar count and int indices, the 64 bits indices being checked by checkIndices64
+func faceColumns64(faces []plyfile.Face64) []plyfile.PlyPropertyData {
+	data := []plyfile.PlyPropertyData{{Prop: *plyfile.New_property("vertex_indices", plyfile.PLY_INT, plyfile.PLY_INT, 0, 1, plyfile.PLY_UCHAR, plyfile.PLY_UCHAR, 0)}}
+	data[0].Lists = make([][]float64, len(faces))
+	for i, f := range faces {
+		data[0].Lists[i] = []float64{float64(f.X), float64(f.Y), float64(f.Z)}
+	}
+	return data
+}
+func faceColumns32(faces []plyfile.Face32) []plyfile.PlyPropertyData {
+	data := []plyfile.PlyPropertyData{{Prop: *plyfile.New_property("vertex_indices", plyfile.PLY_INT, plyfile.PLY_INT, 0, 1, plyfile.PLY_UCHAR, plyfile.PLY_UCHAR, 0)}}
+	data[0].Lists = make([][]float64, len(faces))
+	for i, f := range faces {
+		data[0].Lists[i] = []float64{float64(f.X), float64(f.Y), float64(f.Z)}
+	}
+	return data
+}
+
+// writeColumns writes a file holding the vertex and face elements described by their columns
+func writeColumns(filename string, file_type int, vertex_data []plyfile.PlyPropertyData, face_data []plyfile.PlyPropertyData) (err error) {
+	version := float32(1)
+	cplyfile, err := plyfile.PlyOpenForWriting(filename, 2, []string{"vertex", "face"}, file_type, &version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// keep the file only if everything was written
+		if err != nil {
+			plyfile.PlyAbort(cplyfile)
+			return
+		}
+		err = plyfile.PlyClose(cplyfile)
+	}()
+
+	// describe the header
+	elements := []struct {
+		name string
+		data []plyfile.PlyPropertyData
+	}{{"vertex", vertex_data}, {"face", face_data}}
+	for _, elem := range elements {
+		for _, column := range elem.data {
+			if err = plyfile.PlyDescribeProperty(cplyfile, elem.name, column.Prop); err != nil {
+				return err
+			}
+		}
+		if err = plyfile.PlyElementCount(cplyfile, elem.name, plyfile.PlyColumnsLength(elem.data)); err != nil {
+			return err
+		}
+	}
+	if err = plyfile.PlyHeaderComplete(cplyfile); err != nil {
+		return err
+	}
+
+	// write the data
+	for _, elem := range elements {
+		if err = plyfile.PlyPutElementSetup(cplyfile, elem.name); err != nil {
+			return err
+		}
+		if err = plyfile.PlyPutElementProperties(cplyfile, elem.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMono writes a file holding vertices made of x, y and z of the given type and triangles, each element type with one call to PlyPutElementHuge
+func writeMono(filename string, typ int, vertices interface{}, num_vertices int, faces []plyfile.FaceReadingHuge) (err error) {
+	version := float32(1)
+	cplyfile, err := plyfile.PlyOpenForWriting(filename, 2, []string{"vertex", "face"}, plyfile.PLY_BINARY_LE, &version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// keep the file only if everything was written
+		if err != nil {
+			plyfile.PlyAbort(cplyfile)
+			return
+		}
+		err = plyfile.PlyClose(cplyfile)
+	}()
+
+	// describe the header
+	for _, name := range []string{"x", "y", "z"} {
+		if err = plyfile.PlyDescribeProperty(cplyfile, "vertex", *plyfile.New_property(name, typ, typ, 0, 0, 0, 0, 0)); err != nil {
+			return err
+		}
+	}
+	if err = plyfile.PlyDescribeProperty(cplyfile, "face", faceColumns32(nil)[0].Prop); err != nil {
+		return err
+	}
+	if err = plyfile.PlyElementCount(cplyfile, "vertex", num_vertices); err != nil {
+		return err
+	}
+	if err = plyfile.PlyElementCount(cplyfile, "face", len(faces)); err != nil {
+		return err
+	}
+	if err = plyfile.PlyHeaderComplete(cplyfile); err != nil {
+		return err
+	}
+
+	// write the data
+	if err = plyfile.PlyPutElementSetup(cplyfile, "vertex"); err != nil {
+		return err
+	}
+	if err = plyfile.PlyPutElementHuge(cplyfile, vertices); err != nil {
+		return err
+	}
+	if err = plyfile.PlyPutElementSetup(cplyfile, "face"); err != nil {
+		return err
+	}
+	return plyfile.PlyPutElementHuge(cplyfile, faces)
+}
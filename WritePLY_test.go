@@ -0,0 +1,62 @@
+package plyReaderRealsense
+
+import (
+	"dataprocessing/plyfile"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWritePLYMonoRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "mono.ply")
+	vertices := []plyfile.VertexMono64{{X: 0.1, Y: -2, Z: 3}, {X: 4, Y: 5, Z: 6}, {X: 7, Y: 8, Z: 9}}
+	faces := []plyfile.Face64{{X: 0, Y: 1, Z: 2}, {X: 2, Y: 1, Z: 0}}
+	if err := WritePLYMono64(filename, vertices, faces); err != nil {
+		t.Fatal(err)
+	}
+	read_vertices, read_faces, err := ReadPLYMono64E(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read_vertices, vertices) || !reflect.DeepEqual(read_faces, faces) {
+		t.Errorf("read %v %v, want %v %v", read_vertices, read_faces, vertices, faces)
+	}
+}
+
+func TestWritePLYNormalRoundTrip(t *testing.T) {
+	vertices := []plyfile.VertexNormal64{{X: 0.1, Y: 1e-300, Z: -3, NX: 0, NY: 0.6, NZ: 0.8}, {X: 1, Y: 2, Z: 3, NX: 1, NY: 0, NZ: 0}}
+	faces := []plyfile.Face64{{X: 0, Y: 1, Z: 1}}
+	for _, file_type := range []int{plyfile.PLY_ASCII, plyfile.PLY_BINARY_LE, plyfile.PLY_BINARY_BE} {
+		filename := filepath.Join(t.TempDir(), "normal.ply")
+		if err := WritePLYNormal64(filename, vertices, faces, file_type); err != nil {
+			t.Fatal(err)
+		}
+		read_vertices, read_faces, err := ReadPLYNormal64(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(read_vertices, vertices) || !reflect.DeepEqual(read_faces, faces) {
+			t.Errorf("file type %d: read %v %v, want %v %v", file_type, read_vertices, read_faces, vertices, faces)
+		}
+	}
+}
+
+func TestWriteIndicesOutOfRange(t *testing.T) {
+	vertices := []plyfile.VertexMono64{{X: 1, Y: 2, Z: 3}}
+	normals := []plyfile.VertexNormal64{{X: 1, Y: 2, Z: 3}}
+	for _, index := range []int64{math.MaxInt32 + 1, math.MinInt32 - 1, math.MaxInt64} {
+		faces := []plyfile.Face64{{X: 0, Y: 0, Z: 0}, {X: 0, Y: index, Z: 0}}
+		filename := filepath.Join(t.TempDir(), "big.ply")
+		if err := WritePLYMono64(filename, vertices, faces); err == nil {
+			t.Errorf("WritePLYMono64 wrote index %d", index)
+		}
+		if err := WritePLYNormal64(filename, normals, faces, plyfile.PLY_BINARY_LE); err == nil {
+			t.Errorf("WritePLYNormal64 wrote index %d", index)
+		}
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			t.Errorf("a file was left for index %d", index)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Command plyconvert converts a PLY file between the ascii, binary little endian and binary big endian formats, keeping all its elements, properties, comments and obj_info.
+//
+// Usage:
+//
+//	plyconvert [-format ascii|binary_little_endian|binary_big_endian] [-drop props] [-type changes] [-strip-faces] in.ply out.ply
+//
+// -drop removes properties, given as name (in every element) or element.name, separated by commas.
+// -type changes the type of properties, given as from:to (every property of type from, as double:float) or element.name:to, separated by commas. A change to an integer type fails if a value of the property is fractional or out of the range of the type.
+// -strip-faces removes the face element.
+// The input may be compressed with gzip or bzip2, the output is compressed with gzip when its name ends in .gz.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	plyfile "dataprocessing/plyfile"
+)
+
+// file types accepted by -format
+var formats = map[string]int{
+	"ascii":                plyfile.PLY_ASCII,
+	"binary_little_endian": plyfile.PLY_BINARY_LE,
+	"binary_big_endian":    plyfile.PLY_BINARY_BE,
+	"le":                   plyfile.PLY_BINARY_LE,
+	"be":                   plyfile.PLY_BINARY_BE,
+}
+
+func main() {
+	format := flag.String("format", "binary_little_endian", "format of the output : ascii, binary_little_endian (le) or binary_big_endian (be)")
+	drop := flag.String("drop", "", "comma separated properties to remove, as name or element.name")
+	types := flag.String("type", "", "comma separated type changes, as from:to or element.name:to")
+	stripFaces := flag.Bool("strip-faces", false, "remove the face element")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: plyconvert [-format ascii|binary_little_endian|binary_big_endian] [-drop props] [-type changes] [-strip-faces] in.ply out.ply")
+		os.Exit(2)
+	}
+	file_type, ok := formats[*format]
+	if !ok {
+		fail(fmt.Errorf("unknown format %s", *format))
+	}
+	if err := convert(flag.Arg(0), flag.Arg(1), file_type, split(*drop), split(*types), *stripFaces); err != nil {
+		fail(err)
+	}
+}
+
+// convert writes the file in to out in the given format, without the dropped properties and, if stripFaces, without the faces, after the changes of types
+func convert(in, out string, file_type int, drop, types []string, stripFaces bool) error {
+	doc, err := plyfile.PlyReadDocument(in)
+	if err != nil {
+		return err
+	}
+	doc.Format = file_type
+	if stripFaces {
+		removeElement(doc, "face")
+	}
+	for _, name := range drop {
+		if !dropProperty(doc, name) {
+			return fmt.Errorf("no property %s to drop", name)
+		}
+	}
+	for _, change := range types {
+		if err = changeType(doc, change); err != nil {
+			return err
+		}
+	}
+	return plyfile.PlyWriteDocument(out, doc)
+}
+
+// fail prints the error and stops the program
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "plyconvert:", err)
+	os.Exit(1)
+}
+
+// split returns the non empty values of a comma separated list
+func split(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// removeElement removes the element of the given name from the document
+func removeElement(doc *plyfile.PlyDocument, name string) {
+	elements := doc.Elements[:0]
+	for _, elem := range doc.Elements {
+		if elem.Name != name {
+			elements = append(elements, elem)
+		}
+	}
+	doc.Elements = elements
+}
+
+// dropProperty removes the properties named name, or element.name, and tells whether there was any
+func dropProperty(doc *plyfile.PlyDocument, name string) bool {
+	found := false
+	for i := range doc.Elements {
+		elem := &doc.Elements[i]
+		properties := elem.Properties[:0]
+		for _, column := range elem.Properties {
+			if column.Prop.Name == name || elem.Name+"."+column.Prop.Name == name {
+				found = true
+				continue
+			}
+			properties = append(properties, column)
+		}
+		elem.Properties = properties
+	}
+	return found
+}
+
+// changeType applies a change of type, from:to for every property of type from or element.name:to for one property. The values of the lists are changed too, not their lengths.
+func changeType(doc *plyfile.PlyDocument, change string) error {
+	parts := strings.Split(change, ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("bad type change %s, expected from:to or element.name:to", change)
+	}
+	to := plyfile.TypeConverter(parts[1])
+	if to == 0 {
+		return fmt.Errorf("unknown type %s", parts[1])
+	}
+	from := plyfile.TypeConverter(parts[0])
+
+	found := false
+	for i := range doc.Elements {
+		elem := &doc.Elements[i]
+		for j := range elem.Properties {
+			prop := &elem.Properties[j].Prop
+			if (from != 0 && prop.External_type == from) || elem.Name+"."+prop.Name == parts[0] {
+				if err := checkType(elem.Name, elem.Properties[j], to); err != nil {
+					return err
+				}
+				prop.External_type, prop.Internal_type = to, to
+				found = true
+			}
+		}
+	}
+	if !found && from == 0 {
+		return fmt.Errorf("no property %s to change", parts[0])
+	}
+	return nil
+}
+
+// checkType returns an error if a value of the property can not be written in the type to without being changed, the floats being only rounded
+func checkType(element string, prop plyfile.PlyPropertyData, to int) error {
+	min, max, integer := plyfile.PlyTypeRange(to)
+	if !integer {
+		return nil
+	}
+	check := func(value float64) error {
+		if value < min || value > max || value != math.Trunc(value) {
+			return fmt.Errorf("can not change %s.%s to %s: value %g does not fit", element, prop.Prop.Name, plyfile.TypeConverterInverse(to), value)
+		}
+		return nil
+	}
+	for _, value := range prop.Values {
+		if err := check(value); err != nil {
+			return err
+		}
+	}
+	for _, list := range prop.Lists {
+		for _, value := range list {
+			if err := check(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
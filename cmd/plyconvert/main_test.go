@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	plyfile "dataprocessing/plyfile"
+)
+
+// a big endian capture with sized type names, comments, obj_info, a list of doubles and an element without data
+var capture = append([]byte("ply\nformat binary_big_endian 1.0\ncomment made by hand\nobj_info num_cols 640\n"+
+	"element vertex 2\nproperty float32 x\nproperty float32 y\nproperty uint8 confidence\n"+
+	"element face 1\nproperty list uint8 int32 vertex_indices\n"+
+	"element material 1\nproperty list int16 float64 params\n"+
+	"element empty 0\nproperty int a\nend_header\n"),
+	0x3d, 0xcc, 0xcc, 0xcd, 0xc0, 0x00, 0x00, 0x00, 200, // 0.1 -2 200
+	0x3f, 0x80, 0x00, 0x00, 0x40, 0x40, 0x00, 0x00, 7, // 1 3 7
+	3, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, // 3 0 1 1
+	0, 1, 0x3f, 0xb9, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a) // 1 0.1
+
+func writeCapture(t *testing.T) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "capture.ply")
+	if err := os.WriteFile(filename, capture, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	in := writeCapture(t)
+	dir := t.TempDir()
+	previous := in
+	for i, file_type := range []int{plyfile.PLY_ASCII, plyfile.PLY_BINARY_LE, plyfile.PLY_ASCII, plyfile.PLY_BINARY_BE} {
+		out := filepath.Join(dir, string(rune('a'+i))+".ply")
+		if err := convert(previous, out, file_type, nil, nil, false); err != nil {
+			t.Fatal(err)
+		}
+		header, err := plyfile.PlyInspect(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Format != []string{"ascii", "binary_little_endian", "ascii", "binary_big_endian"}[i] || len(header.Elements) != 4 || len(header.Comments) != 1 || len(header.ObjInfo) != 1 {
+			t.Errorf("step %d: header %v", i, header)
+		}
+		previous = out
+	}
+
+	// back to big endian, the file is the same byte for byte
+	content, _ := os.ReadFile(previous)
+	if !bytes.Equal(content, capture) {
+		t.Errorf("round trip differs:\n%q\n%q", content, capture)
+	}
+}
+
+func TestConvertOptions(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.ply")
+	if err := convert(writeCapture(t), out, plyfile.PLY_BINARY_LE, []string{"confidence"}, []string{"float64:float32", "vertex.x:double"}, true); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := plyfile.PlyReadDocument(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Element("face") != nil {
+		t.Error("the faces were not removed")
+	}
+	vertex := doc.Element("vertex")
+	if len(vertex.Properties) != 2 || plyfile.PlyFindProperty(vertex.Properties, "confidence") != nil {
+		t.Errorf("vertex properties %v", vertex.Properties)
+	}
+	if x := plyfile.PlyFindProperty(vertex.Properties, "x"); x.Prop.External_type != plyfile.PLY_DOUBLE || x.Values[0] != float64(float32(0.1)) {
+		t.Errorf("x = %v", x)
+	}
+	if params := doc.Element("material").Properties[0]; params.Prop.External_type != plyfile.PLY_FLOAT || params.Lists[0][0] != float64(float32(0.1)) {
+		t.Errorf("params = %v", params)
+	}
+
+	// narrowing keeps the values that fit and refuses the others, naming the property
+	if err := convert(writeCapture(t), out, plyfile.PLY_ASCII, nil, []string{"int:uchar", "vertex.y:short"}, false); err != nil {
+		t.Fatal(err)
+	}
+	narrowing := []struct {
+		change string
+		want   string
+	}{
+		{"double:int", "can not change material.params to int: value 0.1 does not fit"},
+		{"vertex.x:short", "can not change vertex.x to short: value 0.1"},
+		{"vertex.y:uint", "can not change vertex.y to uint: value -2 does not fit"},
+		{"uchar:char", "can not change vertex.confidence to char: value 200 does not fit"},
+	}
+	for _, test := range narrowing {
+		out := filepath.Join(t.TempDir(), "narrow.ply")
+		if err := convert(writeCapture(t), out, plyfile.PLY_ASCII, nil, []string{test.change}, false); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: err = %v, want %q", test.change, err, test.want)
+		}
+		if _, err := os.Stat(out); !os.IsNotExist(err) {
+			t.Errorf("%s: a file was written", test.change)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	in := writeCapture(t)
+	tests := []struct {
+		name  string
+		drop  []string
+		types []string
+	}{
+		{"unknown property", []string{"nope"}, nil},
+		{"unknown element property", []string{"face.x"}, nil},
+		{"unknown type", nil, []string{"double:bogus"}},
+		{"bad change", nil, []string{"float"}},
+		{"unknown changed property", nil, []string{"vertex.nope:float"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "out.ply")
+			if err := convert(in, out, plyfile.PLY_ASCII, test.drop, test.types, false); err == nil {
+				t.Error("no error")
+			}
+			if _, err := os.Stat(out); !os.IsNotExist(err) {
+				t.Error("a file was written")
+			}
+		})
+	}
+}
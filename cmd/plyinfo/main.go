@@ -0,0 +1,72 @@
+// Command plyinfo prints what PLY files contain : their format, comments, elements with their counts and properties, and whether the size of each file matches its header.
+//
+// Usage:
+//
+//	plyinfo [-json] file.ply...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	plyfile "dataprocessing/plyfile"
+)
+
+// description of one file in the JSON output
+type report struct {
+	File string `json:"file"`
+	*plyfile.PlyHeader
+	Error string `json:"error,omitempty"`
+}
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the headers as a JSON array")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: plyinfo [-json] file.ply...")
+		os.Exit(2)
+	}
+	os.Exit(run(os.Stdout, os.Stderr, flag.Args(), *asJSON))
+}
+
+// run describes the files on stdout, as text or as a JSON array, and the files it can not read on stderr. It returns the exit status, 1 if a file could not be read.
+func run(stdout, stderr io.Writer, filenames []string, asJSON bool) int {
+	status := 0
+	var reports []report
+	for i, filename := range filenames {
+		header, err := plyfile.PlyInspect(filename)
+		if err != nil {
+			fmt.Fprintf(stderr, "plyinfo: %s: %v\n", filename, err)
+			status = 1
+		}
+
+		if asJSON {
+			r := report{File: filename, PlyHeader: header}
+			if err != nil {
+				r.Error = err.Error()
+			}
+			reports = append(reports, r)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "%s\n%s", filename, header)
+	}
+
+	if asJSON {
+		out, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Fprintln(stderr, "plyinfo:", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(out))
+	}
+	return status
+}
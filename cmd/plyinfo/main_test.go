@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// an ascii file of 2 vertices and a face
+const asciiPLY = "ply\nformat ascii 1.0\ncomment made by hand\nelement vertex 2\nproperty float x\nproperty float y\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n1 2\n3 4\n3 0 1 1\n"
+
+func writeFiles(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.ply")
+	if err := os.WriteFile(good, []byte(asciiPLY), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return good, filepath.Join(dir, "missing.ply")
+}
+
+func TestRunText(t *testing.T) {
+	good, missing := writeFiles(t)
+	var stdout, stderr bytes.Buffer
+	if status := run(&stdout, &stderr, []string{good, missing, good}, false); status != 1 {
+		t.Errorf("status %d, want 1", status)
+	}
+	description := good + "\nformat ascii 1.0\ncomment made by hand\n" +
+		"element vertex 2 (8 bytes)\n  property float x\n  property float y\n" +
+		"element face 1 (8 bytes)\n  property list uchar int vertex_indices\n" +
+		"header 158 bytes, data 16 bytes, file 174 bytes : the size matches\n"
+	if want := description + "\n" + description; stdout.String() != want {
+		t.Errorf("stdout =\n%s\nwant\n%s", stdout.String(), want)
+	}
+	if !strings.HasPrefix(stderr.String(), "plyinfo: "+missing+": ") || strings.Count(stderr.String(), "\n") != 1 {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	good, missing := writeFiles(t)
+	var stdout, stderr bytes.Buffer
+	if status := run(&stdout, &stderr, []string{good, missing}, true); status != 1 {
+		t.Errorf("status %d, want 1", status)
+	}
+	var reports []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &reports); err != nil {
+		t.Fatalf("%v in %s", err, stdout.String())
+	}
+	if len(reports) != 2 {
+		t.Fatalf("%d reports", len(reports))
+	}
+
+	first := reports[0]
+	if first["file"] != good || first["format"] != "ascii" || first["header_size"] != 158.0 || first["data_size"] != 16.0 || first["file_size"] != 174.0 || first["size_matches"] != true || first["error"] != nil {
+		t.Errorf("report %v", first)
+	}
+	elements := first["elements"].([]any)
+	face := elements[1].(map[string]any)
+	property := face["properties"].([]any)[0].(map[string]any)
+	if face["name"] != "face" || face["count"] != 1.0 || property["list"] != true || property["count_type"] != "uchar" || property["type"] != "int" {
+		t.Errorf("face %v", face)
+	}
+	if vertex_property := elements[0].(map[string]any)["properties"].([]any)[0].(map[string]any); vertex_property["list"] != nil {
+		t.Errorf("scalar property %v", vertex_property)
+	}
+
+	if second := reports[1]; second["file"] != missing || second["error"] == nil || second["format"] != nil {
+		t.Errorf("report %v", second)
+	}
+}
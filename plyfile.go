@@ -2,6 +2,9 @@ package plyReaderRealsense
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
@@ -44,6 +47,9 @@ type PlyProperty struct {
 	Count_external int /* file's count type */
 	Count_internal int /* program's count type */
 	Count_offset   int /* offset byte for list count */
+
+	sized_type  bool /* the header read names the type by its size (float32...) */
+	sized_count bool /* the header read names the count type by its size (uint8...) */
 }
 
 //// description of a property and its constructor
@@ -85,14 +91,26 @@ func New_element(name string, num int, props []PlyProperty) *PlyElement {
 
 // description of an .ply file and its constructor
 type PlyFile struct {
-	name       string
-	Fp         *os.File     // file pointer
-	file_type  int          // 1 : ascii; 3 : binary little endian; 2 : binary big endian
-	header_vol int          // number of bytes occupied bt the header
-	version    float32      // version number of file
-	elems      []PlyElement // list of elements
-	comments   []string     // list of comments
-	obj_info   []string     // list of oject ifo
+	name        string
+	Fp          *os.File          // file pointer
+	reader      *bufio.Reader     // buffered reader on the data following the header
+	section     *io.SectionReader // whole content when opened from an io.ReaderAt
+	compressed  bool              // the content is decompressed on the fly, it can only be read in order
+	compressor  io.WriteCloser    // compresses the data written to Fp, nil for a plain file
+	buffer      *bufio.Writer     // buffers the header and the data written, flushed by PlyClose
+	counts_at   []int64           // offsets in the header of the counts written by PlyClose, nil when the counts are known before writing
+	temp        string            // temporary file renamed to name by PlyClose, "" when the file is written in place
+	cursor      int               // index of the element the data reader is on
+	cursor_read int               // number of elements of this type already read
+	sizes       []int64           // size in bytes of the elements of each type, -1 until measured
+	sized_types bool              // write the sized type names (float32...) in the header instead of the classic ones (float...)
+	workers     int               // number of goroutines decoding the binary data, 0 for GOMAXPROCS
+	file_type   int               // 1 : ascii; 3 : binary little endian; 2 : binary big endian
+	header_vol  int               // number of bytes occupied bt the header
+	version     float32           // version number of file
+	elems       []PlyElement      // list of elements
+	comments    []string          // list of comments
+	obj_info    []string          // list of oject ifo
 }
 
 func New_file(name string, fp *os.File, file_type int, hv int, version float32, elems []PlyElement, comments []string, obj_info []string) *PlyFile {
@@ -108,8 +126,70 @@ func New_file(name string, fp *os.File, file_type int, hv int, version float32,
 	}
 }
 
-/* PlyOpenForReading opens a PLY file (specified by filename) and reads in the header information. The returned PlyFile object is used to access header information and data stored in the PLY file. */
+/* PlyOpenForReading opens a PLY file (specified by filename) and reads in the header information. The returned PlyFile object is used to access header information and data stored in the PLY file. The program stops if the file can not be opened or its header can not be read, OpenPLY returns the error instead. */
 func PlyOpenForReading(filename string) (*PlyFile, []string) {
+	plyfile, err := OpenPLY(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return plyfile, PlyGetElementNames(plyfile)
+}
+
+/* OpenPLY opens a PLY file (specified by filename) and reads in the header information like PlyOpenForReading, but returns an error instead of stopping the program : ErrNotPLY, ErrUnsupportedFormat, ErrTruncated or a *PlyHeaderError matching ErrBadHeader. A file compressed with gzip or bzip2 (.ply.gz, .ply.bz2) is recognized by its first bytes and decompressed on the fly. */
+func OpenPLY(filename string) (*PlyFile, error) {
+	// Open the file
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// the reader used for the header is already placed at the start of the data
+	plyfile, err := plyOpenData(bufio.NewReader(file))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	plyfile.name = filename
+	plyfile.Fp = file
+
+	return plyfile, nil
+}
+
+/* PlyOpenReader reads in the header information from a stream (network, HTTP body, stdin...) and keeps reading the data from the same stream, decompressing it if it is compressed with gzip or bzip2. The returned PlyFile has no file pointer : closing the stream is left to the caller. */
+func PlyOpenReader(r io.Reader) (*PlyFile, error) {
+	return plyOpenData(bufio.NewReader(r))
+}
+
+/* PlyOpenReaderAt reads in the header information from the first size bytes of r, the data are then read from the same source. */
+func PlyOpenReaderAt(r io.ReaderAt, size int64) (*PlyFile, error) {
+	section := io.NewSectionReader(r, 0, size)
+	plyfile, err := PlyOpenReader(section)
+	if err != nil {
+		return nil, err
+	}
+	plyfile.section = section
+
+	return plyfile, nil
+}
+
+// plyOpenData reads the header from reader, decompressing the content on the fly if it is compressed with gzip or bzip2, and keeps reading the data from it
+func plyOpenData(reader *bufio.Reader) (*PlyFile, error) {
+	reader, compressed, err := plyDecompress(reader)
+	if err != nil {
+		return nil, err
+	}
+	plyfile, err := plyReadHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	plyfile.reader = reader
+	plyfile.compressed = compressed
+
+	return plyfile, nil
+}
+
+// plyReadHeader reads and parses the header lines until end_header, the returned PlyFile has no file attached yet
+func plyReadHeader(buffer *bufio.Reader) (*PlyFile, error) {
 
 	// Variables of PlyFile
 	var file_type int
@@ -123,41 +203,46 @@ func PlyOpenForReading(filename string) (*PlyFile, []string) {
 	var ele_name string
 	var ele_num int
 
-	prop_edited := false
-
-	// Open the file
-	file, err_F := os.Open(filename)
-	if err_F != nil {
-		log.Fatal(err_F)
-	}
+	ele_open := false
 
 	// Read lines until the end of the header
-	var lines []string
 	linecount := 0
 	bytecount := 0
-	buffer := bufio.NewReader(file)
-	for true {
-		buf, vol_bytes := ReadlineAscii(buffer)
-		lines = append(lines, buf)
-		bytecount += vol_bytes
-
-		//indentify the end of the header
-		if len(lines[linecount]) > 3 {
-			if lines[linecount][0:3] == "end" {
-				break
+	for {
+		line, err := buffer.ReadString('\n')
+		bytecount += len(line)
+		linecount++
+		if err != nil {
+			if linecount == 1 && len(line) == 0 && err == io.EOF {
+				return nil, ErrNotPLY
 			}
+			return nil, plyReadError(err, "the header")
 		}
+		line = strings.TrimRight(line, "\r\n")
 
-		linecount++
-	}
+		// the magic number comes first
+		if linecount == 1 {
+			if strings.TrimSpace(line) != "ply" {
+				return nil, ErrNotPLY
+			}
+			continue
+		}
 
-	// Parsing : deciding what does each line describe
-	for _, i := range lines {
-		split := strings.Fields(i)
+		// Parsing : deciding what does each line describe
+		split := strings.Fields(line)
+		if len(split) == 0 {
+			continue
+		}
 		switch split[0] {
 		// define the format
 		case "format":
-			version, _ = strconv.ParseFloat(split[2], 64)
+			if len(split) < 3 {
+				return nil, &PlyHeaderError{linecount, "incomplete format"}
+			}
+			version, err = strconv.ParseFloat(split[2], 64)
+			if err != nil {
+				return nil, &PlyHeaderError{linecount, "bad version " + split[2]}
+			}
 			switch split[1] {
 			case "ascii":
 				file_type = PLY_ASCII
@@ -165,73 +250,100 @@ func PlyOpenForReading(filename string) (*PlyFile, []string) {
 				file_type = PLY_BINARY_LE
 			case "binary_big_endian":
 				file_type = PLY_BINARY_BE
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, split[1])
 			}
 
 		// add comment
 		case "comment":
-			comments = append(comments, i[8:])
+			comments = append(comments, strings.TrimPrefix(strings.TrimPrefix(line, "comment"), " "))
 
 		// add obj_info
 		case "obj_info":
-			if len(split) > 1 {
-				for i := 1; i < len(split); i++ {
-					obj_info = append(obj_info, split[i])
-				}
-			}
+			obj_info = append(obj_info, strings.TrimPrefix(strings.TrimPrefix(line, "obj_info"), " "))
 
 		//  add a new element
 		case "element":
-			// if we meet a new element and we have edited the properties of an element : we pack this element and add it in to the slice
-			if prop_edited == true {
+			// if we meet a new element : we pack the previous one and add it in to the slice
+			if ele_open {
+				PlyElementStride(props)
 				elems = append(elems, *New_element(ele_name, ele_num, props))
-				prop_edited = false
 				props = nil
 			}
+			if len(split) < 3 {
+				return nil, &PlyHeaderError{linecount, "incomplete element"}
+			}
 			ele_name = split[1]
-			ele_num, _ = strconv.Atoi(split[2])
+			ele_num, err = strconv.Atoi(split[2])
+			if err != nil || ele_num < 0 {
+				return nil, &PlyHeaderError{linecount, "bad count for element " + ele_name}
+			}
+			ele_open = true
 
 		case "property":
+			if !ele_open {
+				return nil, &PlyHeaderError{linecount, "property outside of an element"}
+			}
 			// if this line describes a property list : the second keyword will be "list", the third keyword will describe the type of first data (length of the list) and the fourth keyword for the rest data
 			var isList, count, typ int
 			var name string
-			if split[1] == "list" {
+			if len(split) > 1 && split[1] == "list" {
+				if len(split) < 5 {
+					return nil, &PlyHeaderError{linecount, "incomplete list property"}
+				}
 				isList = 1
 				count = TypeConverter(split[2])
 				typ = TypeConverter(split[3])
 				name = split[4]
+				if count == 0 || typ == 0 {
+					return nil, &PlyHeaderError{linecount, "unknown type for property " + name}
+				}
 			} else {
+				if len(split) < 3 {
+					return nil, &PlyHeaderError{linecount, "incomplete property"}
+				}
 				isList = 0
 				count = 0
 				typ = TypeConverter(split[1])
 				name = split[2]
+				if typ == 0 {
+					return nil, &PlyHeaderError{linecount, "unknown type for property " + name}
+				}
 			}
 			prop := New_property(name, typ, typ, 0, isList, count, count, 0)
+			// remember how the types are named to write them back the same way
+			if isList == 1 {
+				prop.sized_count, prop.sized_type = split[2] != TypeConverterInverse(count), split[3] != TypeConverterInverse(typ)
+			} else {
+				prop.sized_type = split[1] != TypeConverterInverse(typ)
+			}
 			props = append(props, *prop)
 
-			// indicate whether the property is modified
-			prop_edited = true
-
 		case "end_header":
+			if file_type == 0 {
+				return nil, &PlyHeaderError{linecount, "missing format"}
+			}
 			// we pack the last element and add it in to the slice
-			elems = append(elems, *New_element(ele_name, ele_num, props))
+			if ele_open {
+				PlyElementStride(props)
+				elems = append(elems, *New_element(ele_name, ele_num, props))
+			}
+			return New_file("", nil, file_type, bytecount, float32(version), elems, comments, obj_info), nil
+
+		default:
+			return nil, &PlyHeaderError{linecount, "unknown keyword " + split[0]}
 		}
 	}
+}
 
-	plyfile := New_file(filename, file, file_type, bytecount, float32(version), elems, comments, obj_info)
-
+/* PlyGetElementNames returns the names of the elements of the file, in the order of the header */
+func PlyGetElementNames(plyfile *PlyFile) []string {
 	nelems := len(plyfile.elems)
 	elem_names := make([]string, nelems)
 	for i := 0; i < nelems; i++ {
 		elem_names[i] = plyfile.elems[i].name
 	}
-
-	// positioning the file pointer for the next step
-	plyfile.Fp.Close()
-	plyfile.Fp, _ = os.Open(plyfile.name)
-	buf_trash := make([]byte, plyfile.header_vol)
-	_, _ = plyfile.Fp.Read(buf_trash)
-
-	return plyfile, elem_names
+	return elem_names
 }
 
 /* PlyGetElementDescription reads information about a specified element from an open PLY file. Return : the list of properties for this element ; the total number of elements in this file ; the number of properties for this element*/
@@ -244,16 +356,46 @@ func PlyGetElementDescription(plyfile *PlyFile, element_name string) ([]PlyPrope
 	return nil, 0, 0
 }
 
+/* LocateElement places the data reader at the start of the element marked by PlyPutElementSetup, skipping the data of the other elements from their real sizes. size is no more used, it is kept for the callers written when every element was assumed to have this size. */
 func LocateElement(plyfile *PlyFile, size uintptr) {
-	// determine the start of the element to read
-	for elem_index := 0; elem_index < len(plyfile.elems); elem_index++ {
-		if plyfile.elems[elem_index].marker != 1 {
-			buff := make([]byte, size)
-			for index := 0; index < plyfile.elems[elem_index].num; index++ {
-				_, _ = plyfile.Fp.Read(buff)
-			}
+	_ = LocateElementE(plyfile)
+}
+
+/* LocateElementE is LocateElement returning the reading errors */
+func LocateElementE(plyfile *PlyFile) error {
+	for i := 0; i < len(plyfile.elems); i++ {
+		if plyfile.elems[i].marker == 1 {
+			return plySkipTo(plyfile, plyfile.elems[i].name)
+		}
+	}
+	return errors.New("no element marked by PlyPutElementSetup")
+}
+
+// plyDataReader returns the buffered reader the element data are read from
+func plyDataReader(plyfile *PlyFile) *bufio.Reader {
+	if plyfile.reader == nil {
+		plyfile.reader = bufio.NewReader(plyfile.Fp)
+	}
+	return plyfile.reader
+}
+
+// plyElementIndex returns the index of an element in the header, -1 if there is no such element
+func plyElementIndex(plyfile *PlyFile, element_name string) int {
+	for i := 0; i < len(plyfile.elems); i++ {
+		if plyfile.elems[i].name == element_name {
+			return i
 		}
 	}
+	return -1
+}
+
+// plyAdvance records that count elements have been read at the cursor, moving it to the next types of element when they are finished
+func plyAdvance(plyfile *PlyFile, count int) {
+	plyfile.cursor_read += count
+	for plyfile.cursor < len(plyfile.elems) && plyfile.cursor_read >= plyfile.elems[plyfile.cursor].num {
+		plyfile.cursor_read -= plyfile.elems[plyfile.cursor].num
+		plyfile.cursor++
+	}
 }
 
 /* PlyGetComments returns the comments contained in the open PLY file header. */
@@ -266,13 +408,39 @@ func PlyGetObjInfo(plyfile *PlyFile) []string {
 	return plyfile.obj_info
 }
 
-/* PlyClose closes the open plyfile */
-func PlyClose(plyfile *PlyFile) {
-	if plyfile != nil {
-		err := plyfile.Fp.Close()
-		if err != nil {
+/* PlyClose closes the open plyfile, after writing the data still buffered, the counts of a file opened by PlyOpenStreaming and the end of the compressed data of a .ply.gz file. A file opened for writing is then synced to the disk and renamed to its final name, or removed if anything failed. */
+func PlyClose(plyfile *PlyFile) error {
+	if plyfile == nil || plyfile.Fp == nil {
+		return nil
+	}
+	var err error
+	if plyfile.buffer != nil {
+		err = plyfile.buffer.Flush()
+		plyfile.buffer = nil
+	}
+	if plyfile.counts_at != nil && err == nil && plyfile.header_vol > 0 {
+		err = plyPatchCounts(plyfile)
+	}
+	plyfile.counts_at = nil
+	if plyfile.compressor != nil {
+		if cerr := plyfile.compressor.Close(); err == nil {
+			err = cerr
 		}
+		plyfile.compressor = nil
 	}
+	if plyfile.temp != "" && err == nil {
+		err = plyfile.Fp.Sync()
+	}
+	if cerr := plyfile.Fp.Close(); err == nil {
+		err = cerr
+	}
+
+	// the written file replaces the final one only when it is complete
+	if plyfile.temp != "" {
+		err = plyCommit(plyfile.temp, plyfile.name, err)
+		plyfile.temp = ""
+	}
+	return err
 }
 
 // read a line in format ascii, read the appended list of string and its volume in bytes
@@ -282,32 +450,86 @@ func ReadlineAscii(reader *bufio.Reader) (string, int) {
 	return buf, len(string(a)) + 1
 }
 
+/* TypeConverter returns the PLY type of a type name of the header, either classic (char, uchar, short, ushort, int, uint, float, double) or sized (int8, uint8, int16, uint16, int32, uint32, float32, float64). It returns 0 for an unknown name. */
 func TypeConverter(typeStr string) int {
 	switch typeStr {
-	case "float":
-		return PLY_FLOAT
-	case "uchar":
+	case "char", "int8":
+		return PLY_CHAR
+	case "uchar", "uint8":
 		return PLY_UCHAR
-	case "int":
-		return PLY_INT
-	case "short":
+	case "short", "int16":
 		return PLY_SHORT
-	case "float32":
+	case "ushort", "uint16":
+		return PLY_USHORT
+	case "int", "int32":
+		return PLY_INT
+	case "uint", "uint32":
+		return PLY_UINT
+	case "float", "float32":
 		return PLY_FLOAT
+	case "double", "float64":
+		return PLY_DOUBLE
 	}
 	return 0
 }
 
+/* PlyTypeSize returns the number of bytes occupied by a scalar of the given type in a binary PLY file, 0 if the type is unknown */
+func PlyTypeSize(typ int) int {
+	switch typ {
+	case PLY_CHAR, PLY_UCHAR:
+		return 1
+	case PLY_SHORT, PLY_USHORT:
+		return 2
+	case PLY_INT, PLY_UINT, PLY_FLOAT:
+		return 4
+	case PLY_DOUBLE:
+		return 8
+	}
+	return 0
+}
+
+/* TypeConverterInverse returns the classic name of a PLY type, as written in the header */
 func TypeConverterInverse(typeInt int) string {
 	switch typeInt {
-	case PLY_FLOAT:
-		return "float32"
-	case PLY_INT:
-		return "int"
+	case PLY_CHAR:
+		return "char"
 	case PLY_UCHAR:
 		return "uchar"
 	case PLY_SHORT:
 		return "short"
+	case PLY_USHORT:
+		return "ushort"
+	case PLY_INT:
+		return "int"
+	case PLY_UINT:
+		return "uint"
+	case PLY_FLOAT:
+		return "float"
+	case PLY_DOUBLE:
+		return "double"
+	}
+	return ""
+}
+
+/* TypeConverterInverseSized returns the sized name of a PLY type (int8, uint8, int16, uint16, int32, uint32, float32, float64) */
+func TypeConverterInverseSized(typeInt int) string {
+	switch typeInt {
+	case PLY_CHAR:
+		return "int8"
+	case PLY_UCHAR:
+		return "uint8"
+	case PLY_SHORT:
+		return "int16"
+	case PLY_USHORT:
+		return "uint16"
+	case PLY_INT:
+		return "int32"
+	case PLY_UINT:
+		return "uint32"
+	case PLY_FLOAT:
+		return "float32"
+	case PLY_DOUBLE:
+		return "float64"
 	}
 	return ""
 }
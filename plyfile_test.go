@@ -0,0 +1,90 @@
+package plyReaderRealsense
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFile writes a PLY file made of a header and raw data in a temporary directory and returns its name
+func writeTestFile(t *testing.T, name string, header string, data []byte) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, append([]byte(header), data...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+// leBytes encodes values in little endian order
+func leBytes(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return buf.Bytes()
+}
+
+// beBytes encodes values in big endian order
+func beBytes(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		binary.Write(&buf, binary.BigEndian, v)
+	}
+	return buf.Bytes()
+}
+
+func TestPlyOpenReaderAt(t *testing.T) {
+	// the bytes after size are not part of the file
+	content := append([]byte(sizedHeader), sizedData()...)
+	plyfile, err := PlyOpenReaderAt(bytes.NewReader(append(content, 0xff, 0xff)), int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+
+	header, err := PlyGetHeader(plyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.FileSize != int64(len(content)) || !header.SizeMatches {
+		t.Errorf("file %d bytes, size matches %v", header.FileSize, header.SizeMatches)
+	}
+
+	want_vertices := []VertexMono{{X: 1, Y: 2, Z: 3}, {X: 4, Y: 5, Z: 6}}
+	for pass := 0; pass < 2; pass++ {
+		var vertices []VertexMono
+		it := plyfile.Vertices()
+		for it.Next() {
+			vertices = append(vertices, it.Vertex())
+		}
+		if it.Err() != nil || len(vertices) != 2 || vertices[0] != want_vertices[0] || vertices[1] != want_vertices[1] {
+			t.Errorf("pass %d: vertices %v, err %v", pass, vertices, it.Err())
+		}
+
+		// the faces are read after the vertices, then the vertices already passed are read again
+		faces, err := PlyGetElementPropertiesE(plyfile, "face")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list := faces[0].Lists[0]; len(list) != 3 || list[0] != 0 || list[1] != 1 || list[2] != 1 {
+			t.Errorf("pass %d: face %v", pass, list)
+		}
+	}
+
+	// a size cutting the data makes them truncated
+	plyfile, err = PlyOpenReaderAt(bytes.NewReader(content), int64(len(content)-4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer PlyClose(plyfile)
+	if _, err = PlyGetElementPropertiesE(plyfile, "vertex"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = PlyGetElementPropertiesE(plyfile, "face"); !errors.Is(err, ErrTruncated) {
+		t.Errorf("err = %v, want %v", err, ErrTruncated)
+	}
+}
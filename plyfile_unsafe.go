@@ -1,11 +1,13 @@
 package plyReaderRealsense
 
 import (
-	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
 )
 
 
@@ -14,6 +16,11 @@ type Vertex struct {
 	R, G, B uint8
 }
 
+type Vertex64 struct {
+	X, Y, Z float64
+	R, G, B uint8
+}
+
 type VertexMono struct {
 	X, Y, Z float32
 }
@@ -22,6 +29,26 @@ type VertexMono64 struct {
 	X, Y, Z float64
 }
 
+// a vertex with its normal
+type VertexNormal struct {
+	X, Y, Z    float32
+	NX, NY, NZ float32
+}
+
+type VertexNormal64 struct {
+	X, Y, Z    float64
+	NX, NY, NZ float64
+}
+
+// texture coordinates of a vertex, between 0 and 1 from the top left corner of the image
+type TexCoord struct {
+	U, V float32
+}
+
+type TexCoord64 struct {
+	U, V float64
+}
+
 type FaceReading struct {
 	Nverts    byte
 	Vert1 [4]byte
@@ -44,20 +71,43 @@ type Face32 struct {
 	X, Y, Z int32
 }
 
+// widths of the coordinates and of the indices the generic types are made of
+type PlyFloat interface {
+	~float32 | ~float64
+}
+
+type PlyInt interface {
+	~int32 | ~int64
+}
+
+// a vertex and a triangle with the width chosen by the caller
+type Vec3[F PlyFloat] struct {
+	X, Y, Z F
+}
+
+type Tri[I PlyInt] struct {
+	X, Y, Z I
+}
+
+// a face with any number of vertices, as read from a list of vertex indices
+type Polygon32 []int32
+
+type Polygon64 []int64
+
 //all unsafe functions are here
 
-/* PlyOpenForWriting opens a file and returns a pointer to the root struct PlyFile which will contain the header of the data to write */
-func PlyOpenForWriting(filename string, nelems int, elem_names []string, file_type int, version *float32) *PlyFile {
+/* PlyOpenForWriting opens a file and returns a pointer to the root struct PlyFile which will contain the header of the data to write. The data are written to a temporary file in the same directory, which PlyClose renames to filename once complete and PlyAbort removes, so that a crash never leaves a truncated file under the final name. A file named .ply.gz is compressed with gzip, PlyClose writing the end of the compressed data. */
+func PlyOpenForWriting(filename string, nelems int, elem_names []string, file_type int, version *float32) (*PlyFile, error) {
 	// announce variables
 	var list_elems []PlyElement
 	header_vol := 0
 	var list_comments []string
 	var obj_info []string
 
-	// create a file
-	f, err := os.Create(filename)
+	// create a temporary file next to the final one, renamed by PlyClose
+	f, err := plyCreateTemp(filename)
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
 
 	// initialize the slice of PlyElement
@@ -67,43 +117,38 @@ func PlyOpenForWriting(filename string, nelems int, elem_names []string, file_ty
 		list_elems = append(list_elems, *elem)
 	}
 
-	return New_file(filename, f, file_type, header_vol, *version, list_elems, list_comments, obj_info)
+	plyfile := New_file(filename, f, file_type, header_vol, *version, list_elems, list_comments, obj_info)
+	plyfile.temp = f.Name()
+
+	// a .ply.gz file is compressed with gzip while it is written
+	plyfile.compressor = plyCompressor(filename, f)
+	return plyfile, nil
 }
 
 /* PlyElementCount specifies the total number of an element in the struct PlyFile */
-func PlyElementCount(plyfile *PlyFile, elem_name string, num_this int) {
-	elem_miss := true
-
+func PlyElementCount(plyfile *PlyFile, elem_name string, num_this int) error {
 	// iterate in the slice of  among all element to find the one we will modify
 	for i := 0; i < len(plyfile.elems); i++ {
 		if plyfile.elems[i].name == elem_name {
 			plyfile.elems[i].num = num_this
-			elem_miss = false
-			break
+			return nil
 		}
 	}
 
-	if elem_miss {
-		fmt.Println("Element not found for", elem_name)
-	}
+	return fmt.Errorf("element not found for %s", elem_name)
 }
 
 /* PlyDescribeProperty describes a property of an element. */
-func PlyDescribeProperty(plyfile *PlyFile, element_name string, prop PlyProperty) {
-	prop_miss := true
-
+func PlyDescribeProperty(plyfile *PlyFile, element_name string, prop PlyProperty) error {
 	// iterate in the slice of  among all element to find the one we will modify
 	for i := 0; i < len(plyfile.elems); i++ {
 		if plyfile.elems[i].name == element_name {
 			plyfile.elems[i].props = append(plyfile.elems[i].props, prop)
-			prop_miss = false
-			break
+			return nil
 		}
 	}
 
-	if prop_miss {
-		fmt.Println("Element not found for", element_name)
-	}
+	return fmt.Errorf("element not found for %s", element_name)
 }
 
 /* */
@@ -116,164 +161,171 @@ func PlyPutObjInfo(plyfile *PlyFile, obj_info string) {
 }
 
 /* PlyHeaderComplete writes the header to the file*/
-func PlyHeaderComplete(plyfile *PlyFile) {
+func PlyHeaderComplete(plyfile *PlyFile) error {
+	var header strings.Builder
 
 	// write the start of the header
-	_, err := plyfile.Fp.WriteString("ply\n")
-	if err != nil {
-		fmt.Println("Error when writing")
-	}
+	header.WriteString("ply\n")
 
 	// write the file type
-	switch plyfile.file_type {
-	case PLY_ASCII:
-		_, _ = plyfile.Fp.WriteString("format ascii 1.0\n")
-	case PLY_BINARY_BE:
-		_, _ = plyfile.Fp.WriteString("format binary_big_endian 1.0\n")
-	case PLY_BINARY_LE:
-		_, _ = plyfile.Fp.WriteString("format binary_little_endian 1.0\n")
-	default:
-		_, _ = plyfile.Fp.WriteString("bad file type entered\n")
+	if plyFormatName(plyfile.file_type) == "" {
+		return ErrUnsupportedFormat
+	}
+	// a version left to zero is the version 1.0 every reader knows
+	version := plyfile.version
+	if version == 0 {
+		version = 1
 	}
+	header.WriteString("format " + plyFormatName(plyfile.file_type) + " " + plyFormatVersion(version) + "\n")
 
 	// write the comments
-	if len(plyfile.comments) > 0 {
-		for i := 0; i < len(plyfile.comments); i++ {
-			_, _ = plyfile.Fp.WriteString("comment " + plyfile.comments[i] + "\n")
-		}
+	for i := 0; i < len(plyfile.comments); i++ {
+		header.WriteString("comment " + plyfile.comments[i] + "\n")
 	}
 
 	// write object information
-	if len(plyfile.obj_info) > 0 {
-		for i := 0; i < len(plyfile.obj_info); i++ {
-			_, _ = plyfile.Fp.WriteString("obj_info " + plyfile.obj_info[i] + "\n")
-		}
+	for i := 0; i < len(plyfile.obj_info); i++ {
+		header.WriteString("obj_info " + plyfile.obj_info[i] + "\n")
 	}
 
 	// write the information for each element
 	for i := 0; i < len(plyfile.elems); i++ {
-		_, _ = plyfile.Fp.WriteString("element " + plyfile.elems[i].name + " " + strconv.Itoa(plyfile.elems[i].num) + "\n")
+		if plyfile.counts_at != nil {
+			// the count is written on close in the place reserved here
+			header.WriteString("element " + plyfile.elems[i].name + " ")
+			plyfile.counts_at[i] = int64(header.Len())
+			header.WriteString(fmt.Sprintf("%-*d", PLY_COUNT_WIDTH, 0) + "\n")
+		} else {
+			header.WriteString("element " + plyfile.elems[i].name + " " + strconv.Itoa(plyfile.elems[i].num) + "\n")
+		}
 
 		// write the corresponding properties
 		for j := 0; j < len(plyfile.elems[i].props); j++ {
-			if plyfile.elems[i].props[j].Is_list == 0 {
-				_, _ = plyfile.Fp.WriteString("property " + TypeConverterInverse(plyfile.elems[i].props[j].Internal_type) + " " + plyfile.elems[i].props[j].Name + "\n")
+			prop := plyfile.elems[i].props[j]
+			if plyTypeName(plyfile, plyFileType(prop), prop.sized_type) == "" || (prop.Is_list == 1 && plyTypeName(plyfile, plyFileCountType(prop), prop.sized_count) == "") {
+				return fmt.Errorf("unknown type for property %s", prop.Name)
+			}
+			if prop.Is_list == 0 {
+				header.WriteString("property " + plyTypeName(plyfile, plyFileType(prop), prop.sized_type) + " " + prop.Name + "\n")
 			} else {
-				_, _ = plyfile.Fp.WriteString("property list " + TypeConverterInverse(plyfile.elems[i].props[j].Count_internal) + " " + TypeConverterInverse(plyfile.elems[i].props[j].Internal_type) + " " + plyfile.elems[i].props[j].Name + "\n")
+				header.WriteString("property list " + plyTypeName(plyfile, plyFileCountType(prop), prop.sized_count) + " " + plyTypeName(plyfile, plyFileType(prop), prop.sized_type) + " " + prop.Name + "\n")
 			}
 		}
 	}
 
 	// write the end of the header
-		_, _ = plyfile.Fp.WriteString("end_header" + "\n")
+	header.WriteString("end_header" + "\n")
 
+	n, err := io.WriteString(plyDataWriter(plyfile), header.String())
+	plyfile.header_vol = n
+	return err
 }
 
-/* This function is meaningless if we specifies which element to input using different functions such as PlyPutElement and PlyPutElementFace.
-	PlyPutElementSetup marks the element which is to be written next */
-func PlyPutElementSetup(plyfile *PlyFile, b string) {
+/* PlyUseSizedTypes chooses between the classic type names (char, uchar, short, ushort, int, uint, float, double) and the sized ones (int8, uint8, int16, uint16, int32, uint32, float32, float64) for the header written by PlyHeaderComplete. The classic names are written by default. */
+func PlyUseSizedTypes(plyfile *PlyFile, sized bool) {
+	plyfile.sized_types = sized
+}
+
+// plyTypeName returns the name of a type as written in the header of the file, sized telling whether the property was read from a header naming it by its size
+func plyTypeName(plyfile *PlyFile, typ int, sized bool) string {
+	if plyfile.sized_types || sized {
+		return TypeConverterInverseSized(typ)
+	}
+	return TypeConverterInverse(typ)
+}
+
+// plyFileType returns the type a property has in the file : its external type, or its internal type if the external one is not given
+func plyFileType(prop PlyProperty) int {
+	if prop.External_type != PLY_START_TYPE {
+		return prop.External_type
+	}
+	return prop.Internal_type
+}
+
+// plyFileCountType returns the type of the count of a list property in the file
+func plyFileCountType(prop PlyProperty) int {
+	if prop.Count_external != PLY_START_TYPE {
+		return prop.Count_external
+	}
+	return prop.Count_internal
+}
+
+/* This function is meaningless if we specifies which element to input using different functions such as PlyPutElement and PlyPutElementFace in binary mode.
+	PlyPutElementSetup marks the element which is to be written next, whose properties the ascii data follow */
+func PlyPutElementSetup(plyfile *PlyFile, b string) error {
 	ElementMiss := true
 
 	for i := 0; i < len(plyfile.elems); i++ {
 		if plyfile.elems[i].name == b {
 			plyfile.elems[i].marker = 1
 			ElementMiss = false
+		} else {
+			plyfile.elems[i].marker = 0
 		}
 	}
 
 	if ElementMiss {
-		fmt.Println("Element to be written not found")
+		return fmt.Errorf("element to be written not found for %s", b)
 	}
+	return nil
 }
 
 
-/* PlyPutElement writes the element Vertex. In binary mode it is compatible with elements having numbers of scalar properties. In ascii mode it is compatible with elements having numbers of 3 scalar properties named X, Y and Z. */
-func PlyPutElement(plyfile *PlyFile, b Vertex) {
+/* PlyPutElement writes the element Vertex. In binary mode it is compatible with elements having numbers of scalar properties. In ascii mode the fields x, y, z, red, green and blue are written in the types of the properties declared for the element chosen by PlyPutElementSetup, vertex by default, the floats with the shortest representation giving back the same value. An error is returned if they do not make exactly one element. */
+func PlyPutElement(plyfile *PlyFile, b Vertex) error {
 	switch plyfile.file_type {
-	case PLY_BINARY_LE:
-		// write one data
-		buf := new(bytes.Buffer)
-		err := binary.Write(buf, binary.LittleEndian, b)
-		if err != nil {
-			fmt.Println("Error when writing the vertex")
-		}
-		_, err = plyfile.Fp.Write(buf.Bytes())
-		if err != nil {
-			fmt.Println("Error when writing to the file")
+	case PLY_BINARY_LE, PLY_BINARY_BE:
+		if err := plyCountWritten(plyfile, 1); err != nil {
+			return err
 		}
-
-	case PLY_BINARY_BE:
 		// write one data
-		buf := new(bytes.Buffer)
-		err := binary.Write(buf, binary.BigEndian, b)
-		if err != nil {
-			fmt.Println("Error when writing the vertex")
-		}
-		_, err = plyfile.Fp.Write(buf.Bytes())
-		if err != nil {
-			fmt.Println("Error when writing to the file")
-		}
+		return binary.Write(plyDataWriter(plyfile), plyByteOrder(plyfile), b)
 
 	case PLY_ASCII:
-		// write one data
-		str := strconv.FormatFloat(float64(b.X), 'g', 8, 64) + " " + strconv.FormatFloat(float64(b.Y), 'g', 6, 64) + " " + strconv.FormatFloat(float64(b.Z), 'g', 8, 64) + " " + "\n"
-		_, err := plyfile.Fp.WriteString(str)
-		if err != nil {
-			fmt.Println("Error when writing to the file")
-		}
+		// write one data following the declared properties
+		numbers, _ := plyAppendNumbers(nil, reflect.ValueOf(b))
+		return plyPutAscii(plyfile, plyWrittenElement(plyfile, "vertex"), numbers, true)
 	}
+	return ErrUnsupportedFormat
 }
 
-/* PlyPutElementFace writes the element FaceReading. */
-func PlyPutElementFace(plyfile *PlyFile, b FaceReading) {
+/* PlyPutElementNormal writes the element VertexNormal, a vertex followed by its normal, as 6 float properties. In ascii mode they must be the properties declared for the element chosen by PlyPutElementSetup, vertex by default. */
+func PlyPutElementNormal(plyfile *PlyFile, b VertexNormal) error {
 	switch plyfile.file_type {
-	case PLY_BINARY_LE:
-		// write data
-		buf := new(bytes.Buffer)
-		err := binary.Write(buf, binary.LittleEndian, b)
-		if err != nil {
-			fmt.Println("Error when writing the vertex")
-		}
-		_, err = plyfile.Fp.Write(buf.Bytes())
-		if err != nil {
-			fmt.Println("Error when writing to the file")
+	case PLY_BINARY_LE, PLY_BINARY_BE:
+		if err := plyCountWritten(plyfile, 1); err != nil {
+			return err
 		}
-
-	case PLY_BINARY_BE:
 		// write one data
-		buf := new(bytes.Buffer)
-		err := binary.Write(buf, binary.BigEndian, b)
-		if err != nil {
-			fmt.Println("Error when writing the vertex")
-		}
-		_, err = plyfile.Fp.Write(buf.Bytes())
-		if err != nil {
-			fmt.Println("Error when writing to the file")
-		}
+		return binary.Write(plyDataWriter(plyfile), plyByteOrder(plyfile), b)
 
 	case PLY_ASCII:
-		// write one data
+		// write one data following the declared properties
+		numbers, _ := plyAppendNumbers(nil, reflect.ValueOf(b))
+		return plyPutAscii(plyfile, plyWrittenElement(plyfile, "vertex"), numbers, true)
+	}
+	return ErrUnsupportedFormat
+}
+
+/* PlyPutElementFace writes the element FaceReading. In binary mode the bytes of the indices are written as they are. In ascii mode they are read as little endian int32 and written after Nverts, as the list declared for the element chosen by PlyPutElementSetup, face by default : Nverts must be 3, the number of indices a FaceReading holds. */
+func PlyPutElementFace(plyfile *PlyFile, b FaceReading) error {
+	switch plyfile.file_type {
+	case PLY_BINARY_LE, PLY_BINARY_BE:
+		if err := plyCountWritten(plyfile, 1); err != nil {
+			return err
+		}
+		// write data
+		return binary.Write(plyDataWriter(plyfile), plyByteOrder(plyfile), b)
 
-		break
-
-		//for i := 0; i < 4; i++ {
-		//	buf := make([]byte, 0)
-		//	buf = append(buf, b.Verts[i])
-		//	buf = append(buf, b.Verts[i + 1])
-		//	fmt.Println(&buf)
-		//	bytebuff := bytes.NewBuffer(buf)
-		//	var data int16
-		//	binary.Read(bytebuff, binary.BigEndian, &data)
-		//	fmt.Println(data)
-		//
-		//}
-		//fmt.Println(b)
-
-		//_, err := plyfile.Fp.WriteString(str)
-		//if err != nil {
-		//	fmt.Println("Error when writing to the file")
-		//}
+	case PLY_ASCII:
+		// write one data, the length of the list taken from Nverts
+		numbers := []float64{float64(b.Nverts)}
+		for _, vert := range [][4]byte{b.Vert1, b.Vert2, b.Vert3} {
+			numbers = append(numbers, float64(int32(binary.LittleEndian.Uint32(vert[:]))))
+		}
+		return plyPutAscii(plyfile, plyWrittenElement(plyfile, "face"), numbers, true)
 	}
+	return ErrUnsupportedFormat
 }
 
 /* PlyUseExistingForWriting creates a PlyFile object using an existing file pointer */